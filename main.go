@@ -139,6 +139,9 @@ func main() {
 	host := flag.String("host", "c64u", "C64 Ultimate hostname or IP address")
 	verbose := flag.Bool("v", false, "Enable verbose debug logging")
 	assembly64Path := flag.String("assembly64", "~/Downloads/assembly64", "Path to Assembly64 database")
+	telnetBind := flag.String("bind", "127.0.0.1", "Address to bind the telnet server to")
+	telnetPort := flag.Int("telnet-port", 6464, "Telnet server port")
+	telnetGuest := flag.Bool("guest", false, "Read-only guest mode: browsing only, loading disabled")
 	flag.Parse()
 
 	// Set log level.
@@ -158,6 +161,23 @@ func main() {
 			return
 		}
 
+		// Check for "telnet" command.
+		if flag.Arg(0) == "telnet" {
+			// Binding beyond localhost exposes the server to anyone on the
+			// network; require guest mode there so loading files can't be
+			// triggered by a stranger.
+			if *telnetBind != "127.0.0.1" && *telnetBind != "localhost" && !*telnetGuest {
+				fmt.Fprintf(os.Stderr, "Error: binding to %s requires -guest (read-only) mode\n", *telnetBind)
+				os.Exit(1)
+			}
+
+			if err := startTelnetServer(*host, *telnetPort, *assembly64Path, *telnetBind, *telnetGuest); err != nil {
+				fmt.Fprintf(os.Stderr, "Telnet server error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		// CLI mode: run single file.
 		filename := flag.Arg(0)
 		slog.Info("Connecting to C64 Ultimate", "host", *host)
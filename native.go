@@ -251,7 +251,7 @@ func handleInfo(index *SearchIndex, id int) string {
 	b.WriteString(fmt.Sprintf("NAME|%s\n", entry.Name))
 	b.WriteString(fmt.Sprintf("GROUP|%s\n", entry.Group))
 	b.WriteString(fmt.Sprintf("YEAR|%s\n", entry.Year))
-	b.WriteString(fmt.Sprintf("CAT|%s\n", entry.Category))
+	b.WriteString(fmt.Sprintf("CAT|%s\n", entry.CategoryName))
 	b.WriteString(fmt.Sprintf("TYPE|%s\n", entry.FileType))
 	b.WriteString(fmt.Sprintf("PATH|%s\n", entry.Path))
 	b.WriteString(".\n")
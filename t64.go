@@ -0,0 +1,89 @@
+// T64 tape archive parsing and PRG extraction.
+//
+// This is this package's own copy of uploader/t64.go's T64 support - see
+// d64.go's doc comment for why a "package main" can't just import the
+// other one. Keep this in sync with uploader/t64.go if the T64 format
+// handling changes there; extractFirstPRGFromT64 wasn't ported over here,
+// since this client always lets the user pick an entry from the directory
+// browsing screen rather than auto-loading the first one.
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	t64HeaderSize   = 64
+	t64DirEntrySize = 32
+
+	t64EntryTypeNormal = 1
+)
+
+// t64Entry is one directory entry in a T64 archive.
+type t64Entry struct {
+	entryType byte
+	fileType  byte
+	startAddr uint16
+	endAddr   uint16
+	offset    uint32
+	filename  string
+}
+
+// parseT64Directory parses the T64 header and directory, returning every
+// entry in archive order.
+func parseT64Directory(data []byte) ([]t64Entry, error) {
+	if len(data) < t64HeaderSize {
+		return nil, fmt.Errorf("T64 file too small: %d bytes", len(data))
+	}
+
+	usedEntries := binary.LittleEndian.Uint16(data[36:38])
+	if usedEntries == 0 {
+		return nil, fmt.Errorf("T64 archive has no entries")
+	}
+
+	var entries []t64Entry
+	for i := 0; i < int(usedEntries); i++ {
+		offset := t64HeaderSize + i*t64DirEntrySize
+		if offset+t64DirEntrySize > len(data) {
+			break
+		}
+		raw := data[offset : offset+t64DirEntrySize]
+
+		name := strings.TrimRight(string(raw[16:32]), " \x00\xa0")
+
+		entries = append(entries, t64Entry{
+			entryType: raw[0],
+			fileType:  raw[1],
+			startAddr: binary.LittleEndian.Uint16(raw[2:4]),
+			endAddr:   binary.LittleEndian.Uint16(raw[4:6]),
+			offset:    binary.LittleEndian.Uint32(raw[8:12]),
+			filename:  name,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("T64 archive has no readable entries")
+	}
+	return entries, nil
+}
+
+// extractT64Entry extracts entry's payload from t64Data, prefixed with its
+// C64 load address like a regular PRG.
+func extractT64Entry(t64Data []byte, entry t64Entry) ([]byte, error) {
+	length := int(entry.endAddr) - int(entry.startAddr)
+	if length <= 0 {
+		return nil, fmt.Errorf("T64 entry %q has an invalid length", entry.filename)
+	}
+	start := int(entry.offset)
+	end := start + length
+	if start < 0 || end > len(t64Data) {
+		return nil, fmt.Errorf("T64 entry %q extends past end of file", entry.filename)
+	}
+
+	prg := make([]byte, 0, 2+length)
+	prg = append(prg, byte(entry.startAddr), byte(entry.startAddr>>8))
+	prg = append(prg, t64Data[start:end]...)
+	return prg, nil
+}
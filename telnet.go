@@ -27,6 +27,16 @@ var (
 	ansiClear   = "\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n----------------------------------------\r\n"
 )
 
+// PETSCII control codes used once a client negotiates a real C64 terminal
+// type. Printable ASCII 0x20-0x7A shares its codes with PETSCII in
+// lower/upper-case mode, so only the control codes differ from ANSI.
+const (
+	petsciiClearScreen = 0x93
+	petsciiReverseOn   = 0x12
+	petsciiReverseOff  = 0x92
+	petsciiLowercase   = 0x0E // Switch to the lower/upper-case character set.
+)
+
 // Server limits.
 const (
 	maxConnections = 10              // Maximum concurrent connections
@@ -47,11 +57,31 @@ type TelnetModel struct {
 	statusMessage    string
 	err              error
 	assembly64Path   string
+
+	// Container browsing state. Set when the user opens a .d64, .t64, or
+	// .lnx entry instead of loading it directly, so they can pick one file
+	// from the disk image or archive rather than whatever happens to be
+	// first inside it.
+	browsingContainer     bool
+	containerSourceName   string
+	containerEntries      []containerEntry
+	containerCursor       int
+	containerScrollOffset int
+
+	// petscii is true once the client has negotiated a C64 terminal type
+	// (e.g. CCGMS), switching rendering from plain ASCII to real PETSCII
+	// control codes for clear-screen and reverse-video highlighting.
+	petscii bool
+
+	// guestMode disables loading files onto the C64 Ultimate, leaving
+	// browsing and searching available. Set for every session started by
+	// startTelnetServer with guestMode true.
+	guestMode bool
 }
 
 // NewTelnetModel creates a new telnet session model.
 // Defaults to C64 screen dimensions (40x25) for authentic experience.
-func NewTelnetModel(index *SearchIndex, apiClient *APIClient, assembly64Path string) *TelnetModel {
+func NewTelnetModel(index *SearchIndex, apiClient *APIClient, assembly64Path string, guestMode bool) *TelnetModel {
 	m := &TelnetModel{
 		index:            index,
 		apiClient:        apiClient,
@@ -61,6 +91,7 @@ func NewTelnetModel(index *SearchIndex, apiClient *APIClient, assembly64Path str
 		filteredResults:  make([]int, 0),
 		width:            40,
 		height:           25,
+		guestMode:        guestMode,
 	}
 	m.applyFilters()
 	return m
@@ -112,6 +143,11 @@ func (m *TelnetModel) adjustScroll() {
 
 // handleNavigation handles cursor navigation.
 func (m *TelnetModel) handleNavigation(action string) {
+	if m.browsingContainer {
+		m.handleContainerNavigation(action)
+		return
+	}
+
 	// Guard against empty results.
 	if len(m.filteredResults) == 0 {
 		m.cursor = 0
@@ -145,7 +181,57 @@ func (m *TelnetModel) handleNavigation(action string) {
 	}
 }
 
-// loadSelectedEntry loads the selected entry to C64 Ultimate.
+// handleContainerNavigation moves the cursor within the open container's
+// (D64/T64/LNX) file listing, mirroring handleNavigation's behavior for
+// the entry list.
+func (m *TelnetModel) handleContainerNavigation(action string) {
+	if len(m.containerEntries) == 0 {
+		m.containerCursor = 0
+		m.containerScrollOffset = 0
+		return
+	}
+
+	viewHeight := m.height - 8
+	if viewHeight < 5 {
+		viewHeight = 5
+	}
+	adjust := func() {
+		if m.containerCursor < m.containerScrollOffset {
+			m.containerScrollOffset = m.containerCursor
+		} else if m.containerCursor >= m.containerScrollOffset+viewHeight {
+			m.containerScrollOffset = m.containerCursor - viewHeight + 1
+		}
+	}
+
+	switch action {
+	case "up":
+		if m.containerCursor > 0 {
+			m.containerCursor--
+			adjust()
+		}
+	case "down":
+		if m.containerCursor < len(m.containerEntries)-1 {
+			m.containerCursor++
+			adjust()
+		}
+	case "pgup":
+		m.containerCursor = max(0, m.containerCursor-10)
+		adjust()
+	case "pgdown":
+		m.containerCursor = min(len(m.containerEntries)-1, m.containerCursor+10)
+		adjust()
+	case "home":
+		m.containerCursor = 0
+		m.containerScrollOffset = 0
+	case "end":
+		m.containerCursor = max(0, len(m.containerEntries)-1)
+		adjust()
+	}
+}
+
+// loadSelectedEntry loads the selected entry to C64 Ultimate. For .d64
+// entries, it opens the directory for browsing instead of loading the
+// whole disk image, so the user can pick which file on the disk to run.
 func (m *TelnetModel) loadSelectedEntry() error {
 	if len(m.filteredResults) == 0 {
 		return fmt.Errorf("no entry selected")
@@ -153,6 +239,19 @@ func (m *TelnetModel) loadSelectedEntry() error {
 
 	entry := m.index.Entries[m.filteredResults[m.cursor]]
 
+	switch entry.FileType {
+	case "d64":
+		return m.openD64Directory(entry)
+	case "t64":
+		return m.openT64Directory(entry)
+	case "lnx":
+		return m.openLNXDirectory(entry)
+	}
+
+	if m.guestMode {
+		return fmt.Errorf("guest mode: loading is disabled")
+	}
+
 	// Read file.
 	data, err := os.ReadFile(entry.FullPath)
 	if err != nil {
@@ -161,7 +260,7 @@ func (m *TelnetModel) loadSelectedEntry() error {
 
 	// Call appropriate API based on file type.
 	switch entry.FileType {
-	case "d64", "d71", "d81", "g64", "g71":
+	case "d71", "d81", "g64", "g71":
 		err = m.apiClient.runDiskImage(data, entry.FileType, filepath.Base(entry.FullPath))
 	case "prg":
 		err = m.apiClient.runPRG(data)
@@ -180,6 +279,155 @@ func (m *TelnetModel) loadSelectedEntry() error {
 	return nil
 }
 
+// containerEntry is a single file inside an open disk image or archive
+// (D64 directory, T64 tape, or LNX bundle), normalized so the same
+// browsing screen can list and load from any of them.
+type containerEntry struct {
+	filename string
+	typeName string // Display type, e.g. "PRG", "SEQ", "DEL".
+	loadable bool   // Whether extract can be called to run this entry.
+	extract  func() ([]byte, error)
+}
+
+// openContainer switches the UI into container browsing mode over entries,
+// replacing any previously open container.
+func (m *TelnetModel) openContainer(entries []containerEntry, sourceName string) {
+	m.containerEntries = entries
+	m.containerSourceName = sourceName
+	m.containerCursor = 0
+	m.containerScrollOffset = 0
+	m.browsingContainer = true
+	m.statusMessage = ""
+}
+
+// openD64Directory reads a .d64 image and switches the UI into directory
+// browsing mode so the user can select one of its files.
+func (m *TelnetModel) openD64Directory(entry ReleaseEntry) error {
+	data, err := os.ReadFile(entry.FullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	dirEntries, err := listDirectory(data)
+	if err != nil {
+		return fmt.Errorf("failed to read D64 directory: %w", err)
+	}
+
+	entries := make([]containerEntry, len(dirEntries))
+	for i, de := range dirEntries {
+		de := de
+		entries[i] = containerEntry{
+			filename: de.filename,
+			typeName: d64FileTypeName(de.fileType),
+			loadable: de.fileType == fileTypePRG,
+			extract: func() ([]byte, error) {
+				return extractFileData(data, int(de.track), int(de.sector))
+			},
+		}
+	}
+
+	m.openContainer(entries, entry.Name)
+	return nil
+}
+
+// openT64Directory reads a .t64 tape archive and switches the UI into
+// directory browsing mode so the user can select one of its files.
+func (m *TelnetModel) openT64Directory(entry ReleaseEntry) error {
+	data, err := os.ReadFile(entry.FullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	t64Entries, err := parseT64Directory(data)
+	if err != nil {
+		return fmt.Errorf("failed to read T64 directory: %w", err)
+	}
+
+	entries := make([]containerEntry, len(t64Entries))
+	for i, te := range t64Entries {
+		te := te
+		entries[i] = containerEntry{
+			filename: te.filename,
+			typeName: "PRG",
+			loadable: te.entryType == t64EntryTypeNormal,
+			extract: func() ([]byte, error) {
+				return extractT64Entry(data, te)
+			},
+		}
+	}
+
+	m.openContainer(entries, entry.Name)
+	return nil
+}
+
+// openLNXDirectory reads a .lnx Lynx archive and switches the UI into
+// directory browsing mode so the user can select one of its files.
+func (m *TelnetModel) openLNXDirectory(entry ReleaseEntry) error {
+	data, err := os.ReadFile(entry.FullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lnxEntries, _, err := parseLNXDirectory(data)
+	if err != nil {
+		return fmt.Errorf("failed to read Lynx directory: %w", err)
+	}
+
+	entries := make([]containerEntry, len(lnxEntries))
+	for i, le := range lnxEntries {
+		le := le
+		entries[i] = containerEntry{
+			filename: le.filename,
+			typeName: strings.ToUpper(string(le.fileType)),
+			loadable: le.fileType == 'P',
+			extract: func() ([]byte, error) {
+				return extractLNXEntry(data, le)
+			},
+		}
+	}
+
+	m.openContainer(entries, entry.Name)
+	return nil
+}
+
+// closeContainer leaves container browsing mode and returns to the entry
+// list.
+func (m *TelnetModel) closeContainer() {
+	m.browsingContainer = false
+	m.containerEntries = nil
+}
+
+// loadContainerFile extracts the selected file from the open container and
+// runs it on the C64 Ultimate. Only entries marked loadable (PRG files) can
+// be run directly.
+func (m *TelnetModel) loadContainerFile() error {
+	if m.guestMode {
+		return fmt.Errorf("guest mode: loading is disabled")
+	}
+
+	if len(m.containerEntries) == 0 || m.containerCursor >= len(m.containerEntries) {
+		return fmt.Errorf("no file selected")
+	}
+
+	file := m.containerEntries[m.containerCursor]
+	if !file.loadable {
+		return fmt.Errorf("cannot run %s files directly", file.typeName)
+	}
+
+	data, err := file.extract()
+	if err != nil {
+		return fmt.Errorf("failed to extract file: %w", err)
+	}
+
+	if err := m.apiClient.runPRG(data); err != nil {
+		return fmt.Errorf("failed to load: %w", err)
+	}
+
+	m.statusMessage = fmt.Sprintf("Loaded: %s", file.filename)
+	m.err = nil
+	return nil
+}
+
 // refreshIndex reloads the Assembly64 index from disk.
 func (m *TelnetModel) refreshIndex() error {
 	index, err := loadAssembly64Index(m.assembly64Path)
@@ -195,8 +443,12 @@ func (m *TelnetModel) refreshIndex() error {
 	return nil
 }
 
-// startTelnetServer starts the telnet server.
-func startTelnetServer(c64Host string, port int, assembly64Path string) error {
+// startTelnetServer starts the telnet server. guestMode, when set, puts
+// every session into read-only browsing: loading files onto the C64
+// Ultimate is disabled. This is required (and enforced by main()) whenever
+// bindAddr is anything other than localhost, since the telnet protocol
+// itself has no authentication.
+func startTelnetServer(c64Host string, port int, assembly64Path string, bindAddr string, guestMode bool) error {
 	// Validate port.
 	if port < 1 || port > 65535 {
 		return fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
@@ -210,14 +462,19 @@ func startTelnetServer(c64Host string, port int, assembly64Path string) error {
 	slog.Info("Loaded Assembly64 index", "entries", len(index.Entries))
 
 	// Create TCP listener.
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	addr := fmt.Sprintf("%s:%d", bindAddr, port)
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return fmt.Errorf("failed to start listener: %w", err)
 	}
 	defer listener.Close()
 
-	slog.Info("Telnet server listening", "port", port)
-	fmt.Printf("Telnet server listening on :%d\n", port)
+	slog.Info("Telnet server listening", "addr", addr, "guestMode", guestMode)
+	fmt.Printf("Telnet server listening on %s", addr)
+	if guestMode {
+		fmt.Printf(" (guest mode: read-only)")
+	}
+	fmt.Println()
 
 	// Track active connections.
 	var activeConns int32
@@ -243,13 +500,86 @@ func startTelnetServer(c64Host string, port int, assembly64Path string) error {
 
 		go func(c net.Conn) {
 			defer atomic.AddInt32(&activeConns, -1)
-			handleConnection(c, index, c64Host, assembly64Path)
+			handleConnection(c, index, c64Host, assembly64Path, guestMode)
 		}(conn)
 	}
 }
 
+// negotiateTerminalType asks the client for its telnet TERMINAL-TYPE
+// (RFC 1091) and returns whatever name it reports, or "" if the client
+// doesn't support the option or doesn't answer in time.
+func negotiateTerminalType(conn net.Conn) string {
+	// IAC DO TERMINAL-TYPE (255, 253, 24).
+	if _, err := conn.Write([]byte{255, 253, 24}); err != nil {
+		return ""
+	}
+
+	conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+	defer conn.SetReadDeadline(time.Time{})
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil || n < 3 {
+		return ""
+	}
+	reply := buf[:n]
+
+	// Expect IAC WILL TERMINAL-TYPE (255, 251, 24) before asking for the value.
+	if !(reply[0] == 255 && reply[1] == 251 && reply[2] == 24) {
+		return ""
+	}
+
+	// IAC SB TERMINAL-TYPE SEND IAC SE (255, 250, 24, 1, 255, 240).
+	if _, err := conn.Write([]byte{255, 250, 24, 1, 255, 240}); err != nil {
+		return ""
+	}
+
+	conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+	n, err = conn.Read(buf)
+	if err != nil || n < 6 {
+		return ""
+	}
+	reply = buf[:n]
+
+	// Expect IAC SB TERMINAL-TYPE IS <name> IAC SE (255, 250, 24, 0, ..., 255, 240).
+	if !(reply[0] == 255 && reply[1] == 250 && reply[2] == 24 && reply[3] == 0) {
+		return ""
+	}
+	end := len(reply)
+	if end >= 2 && reply[end-2] == 255 && reply[end-1] == 240 {
+		end -= 2
+	}
+	return string(reply[4:end])
+}
+
+// isPETSCIITerminal reports whether a negotiated terminal type belongs to
+// a real C64 terminal program rather than a generic telnet client.
+func isPETSCIITerminal(termType string) bool {
+	t := strings.ToUpper(termType)
+	return strings.Contains(t, "CCGMS") || strings.Contains(t, "PETSCII") || strings.Contains(t, "C64")
+}
+
+// screenClear returns the byte sequence that clears the screen and resets
+// the cursor, in whichever dialect this client negotiated.
+func (m *TelnetModel) screenClear() string {
+	if m.petscii {
+		return string([]byte{petsciiClearScreen})
+	}
+	return ansiClear
+}
+
+// styleReverse highlights s in reverse video when the client supports real
+// PETSCII control codes; generic telnet clients get s unchanged (ansiBold/
+// ansiMagenta are empty in plain mode, see the comment at the top of this file).
+func (m *TelnetModel) styleReverse(s string) string {
+	if m.petscii {
+		return string([]byte{petsciiReverseOn}) + s + string([]byte{petsciiReverseOff})
+	}
+	return ansiBold + ansiMagenta + s + ansiReset
+}
+
 // handleConnection handles a single telnet connection.
-func handleConnection(conn net.Conn, index *SearchIndex, c64Host string, assembly64Path string) {
+func handleConnection(conn net.Conn, index *SearchIndex, c64Host string, assembly64Path string, guestMode bool) {
 	defer conn.Close()
 	defer slog.Info("Client disconnected", "remote", conn.RemoteAddr())
 
@@ -267,9 +597,21 @@ func handleConnection(conn net.Conn, index *SearchIndex, c64Host string, assembl
 		return
 	}
 
+	// Negotiate terminal type so real C64 terminal programs (CCGMS and
+	// friends) can be switched into proper PETSCII rendering instead of
+	// the plain-ASCII fallback used for generic telnet clients.
+	termType := negotiateTerminalType(conn)
+	slog.Debug("Negotiated terminal type", "remote", conn.RemoteAddr(), "type", termType)
+
 	// Create per-connection API client and model.
 	apiClient := NewAPIClient(c64Host)
-	model := NewTelnetModel(index, apiClient, assembly64Path)
+	model := NewTelnetModel(index, apiClient, assembly64Path, guestMode)
+	model.petscii = isPETSCIITerminal(termType)
+	if model.petscii {
+		// Switch to the lower/upper-case character set so ASCII text
+		// (which shares printable PETSCII codes with it) displays legibly.
+		conn.Write([]byte{petsciiLowercase})
+	}
 
 	// Main loop.
 	// Initial render.
@@ -314,10 +656,14 @@ func handleConnection(conn net.Conn, index *SearchIndex, c64Host string, assembl
 // renderScreen renders the full UI to the connection.
 // Adapts layout for 40-column (C64) vs 80-column terminals.
 func renderScreen(conn net.Conn, m *TelnetModel) error {
+	if m.browsingContainer {
+		return renderContainerScreen(conn, m)
+	}
+
 	var b strings.Builder
 
 	// Clear screen and reset cursor to top-left.
-	b.WriteString(ansiClear)
+	b.WriteString(m.screenClear())
 
 	// Title (shorter for 40-col).
 	b.WriteString(ansiBold + ansiMagenta)
@@ -377,7 +723,7 @@ func renderScreen(conn net.Conn, m *TelnetModel) error {
 
 		for i := start; i < end; i++ {
 			entry := m.index.Entries[m.filteredResults[i]]
-			line := formatEntryTelnet(entry, i == m.cursor, m.width)
+			line := formatEntryTelnet(m, entry, i == m.cursor, m.width)
 			b.WriteString(line)
 			b.WriteString("\r\n")
 		}
@@ -411,6 +757,9 @@ func renderScreen(conn net.Conn, m *TelnetModel) error {
 	} else {
 		b.WriteString("Arrows Tab Enter Q:Quit")
 	}
+	if m.guestMode {
+		b.WriteString(" [GUEST]")
+	}
 	b.WriteString(ansiReset)
 
 	_, err := conn.Write([]byte(b.String()))
@@ -419,7 +768,7 @@ func renderScreen(conn net.Conn, m *TelnetModel) error {
 
 // formatEntryTelnet formats a single entry for telnet display.
 // Adapts layout based on terminal width (40 for C64, 80+ for modern).
-func formatEntryTelnet(entry ReleaseEntry, selected bool, width int) string {
+func formatEntryTelnet(m *TelnetModel, entry ReleaseEntry, selected bool, width int) string {
 	cursor := " "
 	if selected {
 		cursor = ">"
@@ -458,6 +807,104 @@ func formatEntryTelnet(entry ReleaseEntry, selected bool, width int) string {
 	return line
 }
 
+// renderContainerScreen renders the file listing of an open container
+// (D64/T64/LNX), mirroring renderScreen's layout.
+func renderContainerScreen(conn net.Conn, m *TelnetModel) error {
+	var b strings.Builder
+
+	b.WriteString(m.screenClear())
+
+	b.WriteString(ansiBold + ansiMagenta)
+	if m.width <= 40 {
+		b.WriteString(m.containerSourceName)
+	} else {
+		b.WriteString("Directory: " + m.containerSourceName)
+	}
+	b.WriteString(ansiReset)
+	b.WriteString("\r\n\r\n")
+
+	viewHeight := m.height - 8
+	if viewHeight < 5 {
+		viewHeight = 5
+	}
+
+	if len(m.containerEntries) == 0 {
+		b.WriteString(ansiGray + "No files" + ansiReset + "\r\n")
+	} else {
+		start := m.containerScrollOffset
+		end := min(start+viewHeight, len(m.containerEntries))
+
+		for i := start; i < end; i++ {
+			line := formatContainerEntryTelnet(m, m.containerEntries[i], i == m.containerCursor, m.width)
+			b.WriteString(line)
+			b.WriteString("\r\n")
+		}
+	}
+
+	b.WriteString(ansiGray)
+	b.WriteString(fmt.Sprintf("[%d]", len(m.containerEntries)))
+	b.WriteString(ansiReset)
+
+	if m.err != nil {
+		b.WriteString(" " + ansiRed)
+		errStr := m.err.Error()
+		maxErr := m.width - 10
+		if maxErr > 0 && len(errStr) > maxErr {
+			errStr = errStr[:maxErr]
+		}
+		b.WriteString(errStr)
+		b.WriteString(ansiReset)
+	} else if m.statusMessage != "" {
+		b.WriteString(" " + ansiGreen + m.statusMessage + ansiReset)
+	}
+	b.WriteString("\r\n")
+
+	b.WriteString(ansiGray)
+	if m.width <= 40 {
+		b.WriteString("Arr Ent Bks:back")
+	} else {
+		b.WriteString("Arrows Enter:load Backspace/Q:back")
+	}
+	b.WriteString(ansiReset)
+
+	_, err := conn.Write([]byte(b.String()))
+	return err
+}
+
+// formatContainerEntryTelnet formats a single container entry for display.
+func formatContainerEntryTelnet(m *TelnetModel, entry containerEntry, selected bool, width int) string {
+	cursor := " "
+	if selected {
+		cursor = ">"
+	}
+
+	typeName := entry.typeName
+	name := entry.filename
+
+	var line string
+	if width <= 40 {
+		maxNameLen := width - 2 - len(typeName) - 1
+		if maxNameLen < 8 {
+			maxNameLen = 8
+		}
+		if len(name) > maxNameLen {
+			name = name[:maxNameLen-2] + ".."
+		}
+		line = fmt.Sprintf("%s%-*s %s", cursor, maxNameLen, name, typeName)
+	} else {
+		maxNameLen := 30
+		if len(name) > maxNameLen {
+			name = name[:maxNameLen-3] + "..."
+		}
+		line = fmt.Sprintf("%s %-32s %s", cursor, name, typeName)
+	}
+
+	if selected {
+		return m.styleReverse(line)
+	}
+	return line
+}
+
 // readInput reads input from the connection and returns an action string.
 func readInput(conn net.Conn) (string, error) {
 	buf := make([]byte, 32)
@@ -600,12 +1047,19 @@ func handleInput(m *TelnetModel, action string, conn net.Conn) bool {
 
 	switch action {
 	case "quit":
+		if m.browsingContainer {
+			m.closeContainer()
+			return true
+		}
 		return false
 
 	case "up", "down", "pgup", "pgdown", "home", "end":
 		m.handleNavigation(action)
 
 	case "tab":
+		if m.browsingContainer {
+			break
+		}
 		// Cycle through categories.
 		currentIdx := -1
 		for i, cat := range m.index.CategoryOrder {
@@ -621,6 +1075,16 @@ func handleInput(m *TelnetModel, action string, conn net.Conn) bool {
 		m.applyFilters()
 
 	case "enter":
+		if m.browsingContainer {
+			m.statusMessage = "Loading..."
+			m.err = nil
+			renderScreen(conn, m)
+			if err := m.loadContainerFile(); err != nil {
+				m.err = err
+				m.statusMessage = ""
+			}
+			break
+		}
 		m.statusMessage = "Loading..."
 		m.err = nil
 		renderScreen(conn, m)
@@ -630,6 +1094,10 @@ func handleInput(m *TelnetModel, action string, conn net.Conn) bool {
 		}
 
 	case "backspace":
+		if m.browsingContainer {
+			m.closeContainer()
+			break
+		}
 		if len(m.searchQuery) > 0 {
 			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
 			m.cursor = 0
@@ -647,8 +1115,8 @@ func handleInput(m *TelnetModel, action string, conn net.Conn) bool {
 		}
 
 	default:
-		// Printable character for search.
-		if len(action) == 1 && action[0] >= 32 && action[0] <= 126 {
+		// Printable character for search (not while browsing a container).
+		if !m.browsingContainer && len(action) == 1 && action[0] >= 32 && action[0] <= 126 {
 			m.searchQuery += action
 			m.cursor = 0
 			m.scrollOffset = 0
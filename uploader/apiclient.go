@@ -0,0 +1,491 @@
+// APIClient handles communication with the C64 Ultimate REST API and FTP
+// server: uploading PRG/CRT/SID files and disk images, mounting disks,
+// and writing memory via DMA. Every method takes a context.Context so a
+// caller (the TUI's background load queue, in particular) can cancel a
+// request still in flight.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// APIClient handles communication with C64 Ultimate REST API.
+type APIClient struct {
+	Host        string
+	HTTPClient  *http.Client
+	RetryPolicy RetryPolicy
+
+	// uploadCache maps a disk image's SHA-256 content hash to the
+	// remote /Temp path it was last uploaded to, so uploadDiskViaFTPDeduped
+	// can skip re-uploading a disk (e.g. re-running another program
+	// from the same disk) that's already sitting on the C64 Ultimate.
+	uploadCacheMu sync.Mutex
+	uploadCache   map[string]string
+}
+
+// APIResponse represents the standard JSON response from C64 Ultimate API.
+type APIResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// RetryPolicy controls how APIClient retries a failed request. The C64
+// Ultimate is typically reached over Wi-Fi, which can drop a connection
+// mid-upload; a RetryPolicy lets that be retried instead of failing the
+// whole load.
+type RetryPolicy struct {
+	MaxRetries int
+	MinSleep   time.Duration
+	MaxSleep   time.Duration
+}
+
+// NoRetry is the default RetryPolicy: a single attempt, no backoff.
+var NoRetry = RetryPolicy{}
+
+// NewAPIClient creates a new C64 Ultimate API client with retries disabled.
+// Set RetryPolicy on the returned client to enable backoff.
+func NewAPIClient(host string) *APIClient {
+	return &APIClient{
+		Host: host,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		RetryPolicy: NoRetry,
+		uploadCache: make(map[string]string),
+	}
+}
+
+// progressFunc reports sent bytes out of total as an upload proceeds.
+// Callers that don't need progress (CLI subcommands, the background
+// protocol servers) pass nil.
+type progressFunc func(sent, total int)
+
+// countingReader wraps a reader, invoking progress after every Read with
+// the running byte count - how uploadAndRun and uploadDiskViaFTP report
+// per-file upload progress to a TUI load job.
+type countingReader struct {
+	r        io.Reader
+	total    int
+	sent     int
+	progress progressFunc
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.sent += n
+	if c.progress != nil {
+		c.progress(c.sent, c.total)
+	}
+	return n, err
+}
+
+// pacerDecayConstant is how fast the pacer's delay shrinks back towards
+// minSleep after a successful call.
+const pacerDecayConstant = 2
+
+// pacer paces repeated attempts at a flaky remote, sleeping between
+// attempts and backing off exponentially on failure then decaying back
+// down on success. Modeled on the sleep/decay pacer used by cloud
+// storage backends such as rclone's mailru, where a brief drop shouldn't
+// be treated the same as a hard failure.
+type pacer struct {
+	minSleep  time.Duration
+	maxSleep  time.Duration
+	sleepTime time.Duration
+}
+
+// newPacer creates a pacer starting at minSleep.
+func newPacer(minSleep, maxSleep time.Duration) *pacer {
+	return &pacer{
+		minSleep:  minSleep,
+		maxSleep:  maxSleep,
+		sleepTime: minSleep,
+	}
+}
+
+// call sleeps for the current delay (or returns early if ctx is
+// canceled), invokes fn, and adjusts the delay for the next attempt:
+// doubled (up to maxSleep) when fn reports a retryable failure, or
+// decayed (down to minSleep) otherwise.
+func (p *pacer) call(ctx context.Context, fn func() (retry bool, err error)) (retry bool, err error) {
+	select {
+	case <-time.After(p.sleepTime):
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	retry, err = fn()
+	if retry && err != nil {
+		p.sleepTime *= 2
+		if p.sleepTime > p.maxSleep {
+			p.sleepTime = p.maxSleep
+		}
+	} else {
+		p.sleepTime /= pacerDecayConstant
+		if p.sleepTime < p.minSleep {
+			p.sleepTime = p.minSleep
+		}
+	}
+	return retry, err
+}
+
+// withRetry runs fn, retrying up to RetryPolicy.MaxRetries times
+// whenever fn reports its failure as retryable. A zero-value
+// RetryPolicy (NoRetry) runs fn exactly once. ctx cancellation aborts
+// both a retry's sleep and any further attempts.
+func (c *APIClient) withRetry(ctx context.Context, fn func() (retry bool, err error)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if c.RetryPolicy.MaxRetries <= 0 {
+		_, err := fn()
+		return err
+	}
+
+	p := newPacer(c.RetryPolicy.MinSleep, c.RetryPolicy.MaxSleep)
+	var retry bool
+	var err error
+	for attempt := 0; attempt <= c.RetryPolicy.MaxRetries; attempt++ {
+		retry, err = p.call(ctx, fn)
+		if err == nil || !retry {
+			return err
+		}
+		slog.Debug("Retrying after transient failure", "attempt", attempt+1, "error", err)
+	}
+	return err
+}
+
+// isTransientError reports whether err looks like a dropped connection
+// or a rate limit rather than a genuine failure - the former is worth
+// retrying, the latter (bad input, C64 Ultimate API errors) is not.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var ftpErr *textproto.Error
+	if errors.As(err, &ftpErr) && ftpErr.Code >= 400 && ftpErr.Code < 500 {
+		return true
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return false
+}
+
+// httpStatusError reports a 5xx or 429 HTTP response status, returned
+// before any attempt to parse the body as an APIResponse, so
+// isTransientError can tell a dropped/overloaded server (5xx, 429) apart
+// from a request C64 Ultimate rejected outright.
+type httpStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status: %s", e.Status)
+}
+
+// doRequest performs an HTTP request and checks for errors in the
+// response, retrying transient failures per c.RetryPolicy. progress may
+// be nil.
+func (c *APIClient) doRequest(ctx context.Context, method, path string, body []byte, progress progressFunc) error {
+	return c.withRetry(ctx, func() (bool, error) {
+		err := c.doRequestOnce(ctx, method, path, body, progress)
+		return isTransientError(err), err
+	})
+}
+
+// doRequestOnce performs a single attempt of the request doRequest retries.
+func (c *APIClient) doRequestOnce(ctx context.Context, method, path string, body []byte, progress progressFunc) error {
+	reqURL := fmt.Sprintf("http://%s%s", c.Host, path)
+
+	var reqBody io.Reader = bytes.NewReader(body)
+	if progress != nil {
+		reqBody = &countingReader{r: reqBody, total: len(body), progress: progress}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body.
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return &httpStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	// Parse JSON response.
+	var apiResp APIResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+
+	// Check for errors in response.
+	if len(apiResp.Errors) > 0 {
+		return fmt.Errorf("API error: %s", strings.Join(apiResp.Errors, ", "))
+	}
+
+	return nil
+}
+
+// uploadAndRun uploads a file and executes it using the specified endpoint.
+func (c *APIClient) uploadAndRun(ctx context.Context, endpoint string, fileData []byte, progress progressFunc) error {
+	return c.doRequest(ctx, "POST", endpoint, fileData, progress)
+}
+
+// WriteMemory writes data to C64 memory via DMA.
+func (c *APIClient) WriteMemory(ctx context.Context, address string, data []byte) error {
+	path := fmt.Sprintf("/v1/machine:writemem?address=%s", address)
+	return c.doRequest(ctx, "POST", path, data, nil)
+}
+
+// resetMachine resets the C64.
+func (c *APIClient) resetMachine(ctx context.Context) error {
+	return c.doRequest(ctx, "PUT", "/v1/machine:reset", nil, nil)
+}
+
+// rebootMachine reboots the C64 Ultimate device itself, distinct from
+// resetMachine which only resets the C64's CPU and memory state.
+func (c *APIClient) rebootMachine(ctx context.Context) error {
+	return c.doRequest(ctx, "PUT", "/v1/machine:reboot", nil, nil)
+}
+
+// ReadMemory reads length bytes of C64 memory via DMA, starting at the
+// given hex address (same format as WriteMemory's address argument).
+func (c *APIClient) ReadMemory(ctx context.Context, address string, length int) ([]byte, error) {
+	var data []byte
+	err := c.withRetry(ctx, func() (bool, error) {
+		d, err := c.readMemoryOnce(ctx, address, length)
+		data = d
+		return isTransientError(err), err
+	})
+	return data, err
+}
+
+// readMemoryOnce performs a single attempt of the request ReadMemory
+// retries. Unlike doRequestOnce, the response body is the raw memory
+// dump rather than a JSON APIResponse, so it's handled separately.
+func (c *APIClient) readMemoryOnce(ctx context.Context, address string, length int) ([]byte, error) {
+	path := fmt.Sprintf("/v1/machine:readmem?address=%s&length=%d", address, length)
+	reqURL := fmt.Sprintf("http://%s%s", c.Host, path)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &httpStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: unexpected status %s", resp.Status)
+	}
+
+	return body, nil
+}
+
+// runPRG uploads and runs a .prg file.
+func (c *APIClient) runPRG(ctx context.Context, fileData []byte, progress progressFunc) error {
+	slog.Info("Uploading and running .prg file")
+	return c.uploadAndRun(ctx, "/v1/runners:run_prg", fileData, progress)
+}
+
+// runCRT uploads and runs a .crt cartridge file.
+func (c *APIClient) runCRT(ctx context.Context, fileData []byte, progress progressFunc) error {
+	slog.Info("Uploading and running .crt cartridge")
+	return c.uploadAndRun(ctx, "/v1/runners:run_crt", fileData, progress)
+}
+
+// runSID uploads and plays a .sid music file.
+func (c *APIClient) runSID(ctx context.Context, fileData []byte, progress progressFunc) error {
+	slog.Info("Uploading and playing .sid file")
+	return c.uploadAndRun(ctx, "/v1/runners:run_sid", fileData, progress)
+}
+
+// mountDisk mounts a disk image from the filesystem.
+func (c *APIClient) mountDisk(ctx context.Context, imagePath, imageType string) error {
+	path := fmt.Sprintf("/v1/drives/a:mount?image=%s&type=%s&mode=readonly", url.QueryEscape(imagePath), imageType)
+	slog.Info("Mounting disk image from filesystem", "path", imagePath, "type", imageType)
+	return c.doRequest(ctx, "PUT", path, nil, nil)
+}
+
+// removeDisk removes the mounted disk from drive A.
+func (c *APIClient) removeDisk(ctx context.Context) error {
+	slog.Info("Removing previously mounted disk")
+	return c.doRequest(ctx, "PUT", "/v1/drives/a:remove", nil, nil)
+}
+
+// uploadDiskViaFTP uploads a disk image to the /Temp directory via FTP,
+// retrying transient connection drops per c.RetryPolicy.
+func (c *APIClient) uploadDiskViaFTP(ctx context.Context, fileData []byte, filename string, progress progressFunc) (string, error) {
+	targetPath := filepath.Join("/Temp", filename)
+
+	err := c.withRetry(ctx, func() (bool, error) {
+		transport, err := newFileTransport(ctx, transportFTP, c.Host, false)
+		if err != nil {
+			return isTransientError(err), err
+		}
+		defer transport.Close()
+
+		slog.Info("Uploading disk image via FTP", "path", targetPath, "size", len(fileData))
+		if err := transport.Upload(ctx, fileData, targetPath, progress); err != nil {
+			return isTransientError(err), err
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	slog.Info("FTP upload completed", "path", targetPath)
+	return targetPath, nil
+}
+
+// uploadDiskViaFTPDeduped is uploadDiskViaFTP, skipping the upload
+// entirely when a disk with the same SHA-256 content hash - the same
+// hash dbgen records per file, see DBFile.Hash - was already uploaded to
+// this client's remote /Temp directory, e.g. running another program
+// from a disk the user just mounted.
+func (c *APIClient) uploadDiskViaFTPDeduped(ctx context.Context, fileData []byte, filename string, progress progressFunc) (string, error) {
+	sum := sha256.Sum256(fileData)
+	hash := hex.EncodeToString(sum[:])
+
+	c.uploadCacheMu.Lock()
+	cached, ok := c.uploadCache[hash]
+	c.uploadCacheMu.Unlock()
+	if ok {
+		slog.Info("Disk already uploaded, skipping re-upload", "hash", hash, "path", cached)
+		return cached, nil
+	}
+
+	remotePath, err := c.uploadDiskViaFTP(ctx, fileData, filename, progress)
+	if err != nil {
+		return "", err
+	}
+
+	c.uploadCacheMu.Lock()
+	// The upload overwrote whatever disk was at remotePath before, so
+	// any hash still pointing there no longer describes its content.
+	for h, p := range c.uploadCache {
+		if p == remotePath {
+			delete(c.uploadCache, h)
+		}
+	}
+	c.uploadCache[hash] = remotePath
+	c.uploadCacheMu.Unlock()
+	return remotePath, nil
+}
+
+// injectKeyboardCommand injects a BASIC command into the C64 keyboard buffer.
+func (c *APIClient) injectKeyboardCommand(ctx context.Context, command string) error {
+	// C64 keyboard buffer is at $0277-$02A6 (631-678 decimal).
+	// Buffer length counter is at $00C6 (198 decimal).
+
+	// Convert command string to PETSCII bytes.
+	petscii := []byte(strings.ToUpper(command))
+
+	// Write command to keyboard buffer.
+	if err := c.WriteMemory(ctx, "0277", petscii); err != nil {
+		return fmt.Errorf("writing keyboard buffer: %w", err)
+	}
+
+	// Set buffer length.
+	bufferLen := []byte{byte(len(petscii))}
+	if err := c.WriteMemory(ctx, "00C6", bufferLen); err != nil {
+		return fmt.Errorf("writing buffer length: %w", err)
+	}
+
+	return nil
+}
+
+// runDiskImage mounts a disk image and, unless runAfter is false (the
+// "load without run" mode bound to m in the TUI), runs the first
+// extracted PRG via DMA. progress reports the FTP upload's percentage
+// and may be nil.
+func (c *APIClient) runDiskImage(ctx context.Context, fileData []byte, imageType, filename string, runAfter bool, progress progressFunc) error {
+	// Extract first PRG file from disk image.
+	prgData, prgFilename, err := extractFirstPRG(fileData)
+	if err != nil {
+		return fmt.Errorf("extracting PRG from disk image: %w", err)
+	}
+
+	slog.Info("Extracted PRG from disk", "filename", prgFilename, "size", len(prgData), "imageType", imageType)
+
+	// Remove previously mounted disk to free up space.
+	if err := c.removeDisk(ctx); err != nil {
+		// Log but don't fail - disk might not be mounted.
+		slog.Debug("Failed to remove previous disk (may not be mounted)", "error", err)
+	}
+
+	// Upload disk image to /Temp via FTP using hardcoded filename to avoid filling /Temp.
+	hardcodedFilename := "uploaded_disk." + imageType
+	remotePath, err := c.uploadDiskViaFTPDeduped(ctx, fileData, hardcodedFilename, progress)
+	if err != nil {
+		return fmt.Errorf("uploading disk via FTP: %w", err)
+	}
+
+	// Mount the disk image from filesystem for multi-file support.
+	if err := c.mountDisk(ctx, remotePath, imageType); err != nil {
+		return fmt.Errorf("mounting disk image: %w", err)
+	}
+
+	slog.Info("Disk image mounted to drive A")
+
+	if !runAfter {
+		return nil
+	}
+
+	// Run the extracted PRG via DMA for fastest startup.
+	return c.runPRG(ctx, prgData, nil)
+}
@@ -0,0 +1,396 @@
+// Concurrent batch uploads for the `batch` subcommand (see runBatch in
+// main.go). A fixed-size worker pool pulls entries off a shared channel -
+// similar in spirit to a bounded installer's download queue - so N
+// uploads are always in flight regardless of skew between file sizes,
+// while a per-host semaphore keeps any single C64 Ultimate host from
+// being hammered by more than a few of them at once. Progress is
+// rendered as one line per worker plus an aggregate line, redrawn in
+// place each tick.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// batchEntry is one file/URL to upload, optionally destined for a
+// specific host; Host falls back to the batch command's default -host
+// when empty.
+type batchEntry struct {
+	Source string `json:"source"`
+	Host   string `json:"host,omitempty"`
+}
+
+// parseManifest reads a batch job list from path, dispatching on
+// extension: a JSON array of batchEntry, a CSV with source,host columns
+// (host optional), or else one source per line, optionally followed by
+// ",host".
+func parseManifest(path string) ([]batchEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var entries []batchEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("parsing JSON manifest: %w", err)
+		}
+		return entries, nil
+
+	case ".csv":
+		r := csv.NewReader(strings.NewReader(string(data)))
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("parsing CSV manifest: %w", err)
+		}
+		var entries []batchEntry
+		for _, rec := range records {
+			if len(rec) == 0 || strings.TrimSpace(rec[0]) == "" {
+				continue
+			}
+			entry := batchEntry{Source: strings.TrimSpace(rec[0])}
+			if len(rec) > 1 {
+				entry.Host = strings.TrimSpace(rec[1])
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+
+	default:
+		var entries []batchEntry
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			parts := strings.SplitN(line, ",", 2)
+			entry := batchEntry{Source: strings.TrimSpace(parts[0])}
+			if len(parts) > 1 {
+				entry.Host = strings.TrimSpace(parts[1])
+			}
+			entries = append(entries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading manifest: %w", err)
+		}
+		return entries, nil
+	}
+}
+
+// batchState is the resume sidecar's on-disk shape: sources already
+// uploaded successfully in a prior -resume run, so a later run can skip
+// them.
+type batchState struct {
+	Done map[string]bool `json:"done"`
+}
+
+// batchStatePath returns the sidecar path for a manifest, e.g.
+// "games.txt" -> "games.txt.c64uploader-state.json".
+func batchStatePath(manifestPath string) string {
+	return manifestPath + ".c64uploader-state.json"
+}
+
+// loadBatchState reads the resume sidecar next to manifestPath. A
+// missing or malformed file is not an error - it just means nothing has
+// been uploaded yet.
+func loadBatchState(manifestPath string) batchState {
+	data, err := os.ReadFile(batchStatePath(manifestPath))
+	if err != nil {
+		return batchState{Done: make(map[string]bool)}
+	}
+
+	var state batchState
+	if err := json.Unmarshal(data, &state); err != nil || state.Done == nil {
+		return batchState{Done: make(map[string]bool)}
+	}
+	return state
+}
+
+// saveBatchState writes the resume sidecar next to manifestPath.
+func saveBatchState(manifestPath string, state batchState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding resume state: %w", err)
+	}
+	if err := os.WriteFile(batchStatePath(manifestPath), data, 0o644); err != nil {
+		return fmt.Errorf("writing resume state: %w", err)
+	}
+	return nil
+}
+
+// hostLimiter caps how many uploads run concurrently against any single
+// host, independent of the overall -workers pool size, so a batch
+// spanning several hosts doesn't let one slow host hold up the rest.
+type hostLimiter struct {
+	mu    sync.Mutex
+	sems  map[string]chan struct{}
+	limit int
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{sems: make(map[string]chan struct{}), limit: limit}
+}
+
+func (h *hostLimiter) acquire(host string) {
+	h.mu.Lock()
+	sem, ok := h.sems[host]
+	if !ok {
+		sem = make(chan struct{}, h.limit)
+		h.sems[host] = sem
+	}
+	h.mu.Unlock()
+	sem <- struct{}{}
+}
+
+func (h *hostLimiter) release(host string) {
+	h.mu.Lock()
+	sem := h.sems[host]
+	h.mu.Unlock()
+	<-sem
+}
+
+// batchProgress tracks aggregate and per-worker upload progress for the
+// terminal display rendered by render. sentBytes and filesDone are
+// updated from worker goroutines via atomics; workers (the per-slot
+// status text) is guarded by mu since it's read and written as whole
+// strings rather than counters.
+type batchProgress struct {
+	mu         sync.Mutex
+	workers    []string
+	startTime  time.Time
+	totalBytes int64
+	sentBytes  int64
+	filesDone  int32
+	filesTotal int
+}
+
+func (p *batchProgress) setWorker(id int, text string) {
+	p.mu.Lock()
+	if id >= 0 && id < len(p.workers) {
+		p.workers[id] = text
+	}
+	p.mu.Unlock()
+}
+
+// startRendering redraws the progress display every interval until ctx
+// is canceled, at which point it draws one final frame and leaves the
+// cursor below it.
+func (p *batchProgress) startRendering(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		drawnLines := 0
+		for {
+			select {
+			case <-ctx.Done():
+				p.draw(&drawnLines)
+				return
+			case <-ticker.C:
+				p.draw(&drawnLines)
+			}
+		}
+	}()
+}
+
+// draw rewrites the progress block in place, moving the cursor back up
+// over the *drawnLines lines it drew last time before redrawing.
+func (p *batchProgress) draw(drawnLines *int) {
+	p.mu.Lock()
+	workerLines := append([]string(nil), p.workers...)
+	p.mu.Unlock()
+
+	elapsed := time.Since(p.startTime)
+	sent := atomic.LoadInt64(&p.sentBytes)
+	total := atomic.LoadInt64(&p.totalBytes)
+	done := atomic.LoadInt32(&p.filesDone)
+
+	var speed float64
+	if elapsed.Seconds() > 0 {
+		speed = float64(sent) / elapsed.Seconds()
+	}
+	eta := "?"
+	if speed > 0 && total > sent {
+		eta = time.Duration(float64(total-sent) / speed * float64(time.Second)).Round(time.Second).String()
+	}
+
+	summary := fmt.Sprintf("[%d/%d files] %s/%s  %s/s  ETA %s",
+		done, p.filesTotal, formatBytes(sent), formatBytes(total), formatBytes(int64(speed)), eta)
+
+	lines := append(append([]string{}, workerLines...), summary)
+
+	if *drawnLines > 0 {
+		fmt.Printf("\033[%dA", *drawnLines)
+	}
+	for _, line := range lines {
+		fmt.Printf("\033[2K%s\n", line)
+	}
+	*drawnLines = len(lines)
+}
+
+// formatBytes renders n using binary (1024-based) unit prefixes, e.g.
+// "3.4MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// uploadBatchEntry reads or downloads entry.Source and uploads it via
+// client, reporting byte progress into progress under workerID's slot.
+func uploadBatchEntry(ctx context.Context, client *APIClient, entry batchEntry, progress *batchProgress, workerID int) error {
+	var data []byte
+	var err error
+	if isURL(entry.Source) {
+		data, err = downloadURL(entry.Source)
+	} else {
+		data, err = os.ReadFile(entry.Source)
+	}
+	if err != nil {
+		return err
+	}
+
+	if isURL(entry.Source) {
+		// A downloaded file's size wasn't known - and so wasn't counted
+		// into the bar's total - until now.
+		atomic.AddInt64(&progress.totalBytes, int64(len(data)))
+	}
+
+	sentSoFar := 0
+	onProgress := func(sent, total int) {
+		atomic.AddInt64(&progress.sentBytes, int64(sent-sentSoFar))
+		sentSoFar = sent
+		pct := 0
+		if total > 0 {
+			pct = sent * 100 / total
+		}
+		progress.setWorker(workerID, fmt.Sprintf("%s: %d%%", filepath.Base(entry.Source), pct))
+	}
+
+	return uploadAndRunFile(ctx, client, data, entry.Source, onProgress)
+}
+
+// runBatchUpload uploads entries across a bounded pool of workers,
+// applying per-host concurrency limiting, retryPolicy, and (if resume is
+// set) resume-skip and sidecar persistence, while rendering a terminal
+// progress bar. It returns the number of entries that failed.
+func runBatchUpload(ctx context.Context, entries []batchEntry, defaultHost string, workers, hostConcurrency int, retryPolicy RetryPolicy, state batchState, resume bool, manifestPath string) int {
+	var pending []batchEntry
+	var totalBytes int64
+	for _, e := range entries {
+		if resume && state.Done[e.Source] {
+			continue
+		}
+		pending = append(pending, e)
+		if !isURL(e.Source) {
+			if info, err := os.Stat(e.Source); err == nil {
+				totalBytes += info.Size()
+			}
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Println("Nothing to do: all entries already uploaded")
+		return 0
+	}
+
+	progress := &batchProgress{
+		startTime:  time.Now(),
+		totalBytes: totalBytes,
+		filesTotal: len(pending),
+		workers:    make([]string, workers),
+	}
+	renderCtx, stopRender := context.WithCancel(ctx)
+	progress.startRendering(renderCtx, 200*time.Millisecond)
+	defer func() {
+		stopRender()
+		fmt.Println()
+	}()
+
+	limiter := newHostLimiter(hostConcurrency)
+
+	var clientsMu sync.Mutex
+	clients := make(map[string]*APIClient)
+	clientFor := func(host string) *APIClient {
+		clientsMu.Lock()
+		defer clientsMu.Unlock()
+		c, ok := clients[host]
+		if !ok {
+			c = NewAPIClient(host)
+			c.RetryPolicy = retryPolicy
+			clients[host] = c
+		}
+		return c
+	}
+
+	jobs := make(chan batchEntry)
+	var stateMu sync.Mutex
+	var failures int32
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		workerID := w
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				host := entry.Host
+				if host == "" {
+					host = defaultHost
+				}
+
+				progress.setWorker(workerID, fmt.Sprintf("%s -> %s: starting", filepath.Base(entry.Source), host))
+				limiter.acquire(host)
+				err := uploadBatchEntry(ctx, clientFor(host), entry, progress, workerID)
+				limiter.release(host)
+
+				if err != nil {
+					slog.Error("Batch upload failed", "source", entry.Source, "host", host, "error", err)
+					progress.setWorker(workerID, fmt.Sprintf("%s -> %s: FAILED: %v", filepath.Base(entry.Source), host, err))
+					atomic.AddInt32(&failures, 1)
+					continue
+				}
+
+				progress.setWorker(workerID, fmt.Sprintf("%s -> %s: done", filepath.Base(entry.Source), host))
+				atomic.AddInt32(&progress.filesDone, 1)
+
+				if resume {
+					stateMu.Lock()
+					state.Done[entry.Source] = true
+					if err := saveBatchState(manifestPath, state); err != nil {
+						slog.Warn("Failed to save resume state", "error", err)
+					}
+					stateMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, e := range pending {
+		jobs <- e
+	}
+	close(jobs)
+	wg.Wait()
+
+	return int(failures)
+}
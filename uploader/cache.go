@@ -0,0 +1,274 @@
+// Disk-backed cache for catalog entries whose FullPath is a remote URL
+// (see providers.go) rather than a local Assembly64 path, so loading the
+// same release from the TUI twice doesn't mean downloading it twice.
+// Lives in ~/.cache/c64uploader (XDG cache convention), distinct from
+// history.go's state directory.
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// downloadCache manages a store of downloaded catalog entries under dir,
+// each file named by a hash of its URL (see cacheKey), evicted least-recently-used once its total size
+// exceeds maxBytes. maxBytes <= 0 disables eviction.
+type downloadCache struct {
+	dir      string
+	maxBytes int64
+}
+
+// cacheMeta is the sidecar recorded alongside each cached file, so a
+// later Get can tell a complete download from a truncated one without
+// re-fetching it, and a resumed download can detect the remote content
+// having changed out from under it.
+type cacheMeta struct {
+	URL           string `json:"url"`
+	ContentLength int64  `json:"contentLength"`
+	ETag          string `json:"etag,omitempty"`
+}
+
+// newDownloadCache opens (creating if needed) ~/.cache/c64uploader.
+func newDownloadCache(maxBytes int64) (*downloadCache, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	dir := filepath.Join(base, "c64uploader")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	return &downloadCache{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// cacheKey derives a filesystem-safe cache filename from a URL.
+func cacheKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *downloadCache) dataPath(url string) string { return filepath.Join(c.dir, cacheKey(url)) }
+func (c *downloadCache) metaPath(url string) string { return c.dataPath(url) + ".meta.json" }
+func (c *downloadCache) partPath(url string) string { return c.dataPath(url) + ".part" }
+
+// Get returns url's content, serving it straight from the cache when a
+// complete, size-verified download of it is already there, and
+// otherwise downloading it - resuming a previous partial download via
+// an HTTP Range request when the server and ETag allow it. progress
+// reports bytes received out of the total and may be nil.
+func (c *downloadCache) Get(ctx context.Context, url string, progress progressFunc) ([]byte, error) {
+	dataPath, metaPath := c.dataPath(url), c.metaPath(url)
+
+	if meta, ok := c.readMeta(metaPath); ok {
+		if data, err := os.ReadFile(dataPath); err == nil && int64(len(data)) == meta.ContentLength {
+			now := time.Now()
+			os.Chtimes(dataPath, now, now)
+			return data, nil
+		}
+	}
+
+	data, meta, err := c.download(ctx, url, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(dataPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("caching downloaded file: %w", err)
+	}
+	if err := c.writeMeta(metaPath, meta); err != nil {
+		return nil, err
+	}
+
+	c.evict()
+	return data, nil
+}
+
+// download fetches url into partPath(url), resuming a prior partial
+// download via Range when one exists and the server's ETag still
+// matches what we started downloading, and returns the complete content
+// plus its metadata once fully received and size-verified.
+func (c *downloadCache) download(ctx context.Context, url string, progress progressFunc) ([]byte, cacheMeta, error) {
+	partPath, partMetaPath := c.partPath(url), c.partPath(url)+".meta.json"
+
+	var existing int64
+	var storedETag string
+	if partMeta, ok := c.readMeta(partMetaPath); ok {
+		if info, err := os.Stat(partPath); err == nil {
+			existing = info.Size()
+			storedETag = partMeta.ETag
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, cacheMeta{}, fmt.Errorf("building download request: %w", err)
+	}
+	if existing > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", existing))
+		if storedETag != "" {
+			// A server that honors If-Range falls back to a full 200
+			// response by itself once the ETag no longer matches, so
+			// this alone prevents most stale-range splices.
+			req.Header.Set("If-Range", storedETag)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, cacheMeta{}, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	default:
+		return nil, cacheMeta{}, fmt.Errorf("downloading %s: unexpected status %s", url, resp.Status)
+	}
+
+	// The server may ignore our Range request (full 200 response) or
+	// reject it because the file changed since we started - either way,
+	// a plain 200 means we're starting over from the top.
+	resuming := existing > 0 && resp.StatusCode == http.StatusPartialContent
+
+	// Belt and braces for a server that doesn't honor If-Range: if it
+	// still answered 206 against a file whose ETag no longer matches
+	// what we started downloading, our on-disk prefix is from a
+	// different version than this response's remainder. Discard the
+	// partial download and start over rather than splice them.
+	if resuming && storedETag != "" {
+		if etag := resp.Header.Get("ETag"); etag != "" && etag != storedETag {
+			os.Remove(partPath)
+			os.Remove(partMetaPath)
+			return c.download(ctx, url, progress)
+		}
+	}
+
+	if !resuming {
+		existing = 0
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if resuming {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return nil, cacheMeta{}, fmt.Errorf("opening partial download: %w", err)
+	}
+
+	total := existing + resp.ContentLength
+	meta := cacheMeta{URL: url, ContentLength: total, ETag: resp.Header.Get("ETag")}
+	c.writeMeta(partMetaPath, meta)
+
+	var reader io.Reader = resp.Body
+	if progress != nil && total > 0 {
+		reader = &countingReader{r: resp.Body, total: int(total), progress: func(sent, _ int) {
+			progress(int(existing)+sent, int(total))
+		}}
+	}
+
+	_, copyErr := io.Copy(f, reader)
+	closeErr := f.Close()
+	if copyErr != nil {
+		return nil, cacheMeta{}, fmt.Errorf("writing %s: %w", url, copyErr)
+	}
+	if closeErr != nil {
+		return nil, cacheMeta{}, fmt.Errorf("writing %s: %w", url, closeErr)
+	}
+
+	data, err := os.ReadFile(partPath)
+	if err != nil {
+		return nil, cacheMeta{}, fmt.Errorf("reading completed download: %w", err)
+	}
+	if total > 0 && int64(len(data)) != total {
+		return nil, cacheMeta{}, fmt.Errorf("downloaded %s: got %d bytes, expected %d", url, len(data), total)
+	}
+
+	os.Remove(partPath)
+	os.Remove(partMetaPath)
+	return data, meta, nil
+}
+
+func (c *downloadCache) readMeta(path string) (cacheMeta, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheMeta{}, false
+	}
+	var meta cacheMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return cacheMeta{}, false
+	}
+	return meta, true
+}
+
+func (c *downloadCache) writeMeta(path string, meta cacheMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("encoding cache metadata: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing cache metadata: %w", err)
+	}
+	return nil
+}
+
+// evict removes least-recently-used cached entries (by file mtime,
+// bumped on every cache hit in Get) until the cache's total size is
+// within maxBytes.
+func (c *downloadCache) evict() {
+	if c.maxBytes <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type cachedFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cachedFile
+	var total int64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasSuffix(name, ".meta.json") || strings.HasSuffix(name, ".part") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{path: filepath.Join(c.dir, name), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	if total <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		os.Remove(f.path)
+		os.Remove(f.path + ".meta.json")
+		total -= f.size
+	}
+}
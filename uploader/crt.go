@@ -0,0 +1,39 @@
+// CRT cartridge header parsing, for display purposes only (uploadAndRun
+// ships the file unmodified; the C64 Ultimate itself interprets the
+// header).
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	crtSignature  = "C64 CARTRIDGE   "
+	crtHeaderSize = 0x40
+)
+
+// crtHeader is the subset of a .crt file's fixed header useful for
+// display: the cartridge hardware type (e.g. 0 = Normal, 5 = Ocean, 32 =
+// EasyFlash) and the embedded cartridge name.
+type crtHeader struct {
+	hardwareType uint16
+	name         string
+}
+
+// parseCRTHeader validates the "C64 CARTRIDGE" signature and extracts the
+// hardware type and name fields from a .crt file's header.
+func parseCRTHeader(data []byte) (*crtHeader, error) {
+	if len(data) < crtHeaderSize {
+		return nil, fmt.Errorf("CRT file too small: %d bytes", len(data))
+	}
+	if string(data[0:16]) != crtSignature {
+		return nil, fmt.Errorf("not a CRT file: missing %q signature", crtSignature)
+	}
+
+	return &crtHeader{
+		hardwareType: binary.BigEndian.Uint16(data[0x16:0x18]),
+		name:         strings.TrimRight(string(data[0x20:0x40]), "\x00"),
+	}, nil
+}
@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"strings"
 )
 
 // D64 disk image constants.
@@ -56,10 +57,11 @@ func getSectorOffset(track, sector int) int {
 
 // directoryEntry represents a file entry in the D64 directory.
 type directoryEntry struct {
-	fileType byte
-	track    byte
-	sector   byte
-	filename string
+	fileType   byte
+	track      byte
+	sector     byte
+	filename   string
+	blockCount uint16 // File size in 254-byte blocks, from the directory entry.
 }
 
 // parseDirectoryEntry parses a 32-byte directory entry.
@@ -90,11 +92,15 @@ func parseDirectoryEntry(data []byte) *directoryEntry {
 		filename += string(ch)
 	}
 
+	// Bytes 0x1C-0x1D: File size in blocks, little-endian.
+	blockCount := uint16(data[0x1C]) | uint16(data[0x1D])<<8
+
 	return &directoryEntry{
-		fileType: fileType,
-		track:    track,
-		sector:   sector,
-		filename: filename,
+		fileType:   fileType,
+		track:      track,
+		sector:     sector,
+		filename:   filename,
+		blockCount: blockCount,
 	}
 }
 
@@ -149,6 +155,51 @@ func scanDirectorySector(sectorData []byte) *directoryEntry {
 	return nil
 }
 
+// listDirectory scans all directory sectors and returns every entry, in
+// directory order, for interactive browsing (unlike
+// findFirstPRGInDirectory, which stops at the first PRG).
+func listDirectory(d64Data []byte) ([]directoryEntry, error) {
+	var entries []directoryEntry
+
+	currentTrack := directoryTrack
+	currentSector := directorySector
+
+	for {
+		offset := getSectorOffset(currentTrack, currentSector)
+		if offset < 0 || offset+bytesPerSector > len(d64Data) {
+			break
+		}
+
+		sectorData := d64Data[offset : offset+bytesPerSector]
+		nextTrack := sectorData[0x00]
+		nextSector := sectorData[0x01]
+
+		for i := 0; i < 8; i++ {
+			entryOffset := 0x02 + (i * 32)
+			// The 8th entry runs to the end of the sector, 2 bytes short
+			// of 32; pad it so parseDirectoryEntry accepts it.
+			var raw [32]byte
+			copy(raw[:], sectorData[entryOffset:])
+
+			entry := parseDirectoryEntry(raw[:])
+			if entry != nil && entry.track != 0 {
+				entries = append(entries, *entry)
+			}
+		}
+
+		if nextTrack == 0 {
+			break
+		}
+		currentTrack = int(nextTrack)
+		currentSector = int(nextSector)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no files found in D64 image")
+	}
+	return entries, nil
+}
+
 // extractFileData follows the sector chain to extract file data from D64.
 func extractFileData(d64Data []byte, startTrack, startSector int) ([]byte, error) {
 	var fileData []byte
@@ -202,6 +253,44 @@ func validateD64Size(d64Data []byte) error {
 	return nil
 }
 
+// extractFirstProgram extracts the first loadable PRG from a disk image or
+// archive container, dispatching on file extension. This is the entry
+// point container-agnostic callers (e.g. run-on-Ultimate flows) should use
+// instead of calling a format-specific extractor directly.
+func extractFirstProgram(data []byte, ext string) ([]byte, string, error) {
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "d64":
+		return extractFirstPRG(data)
+	case "t64":
+		return extractFirstPRGFromT64(data)
+	case "lnx":
+		return extractFirstPRGFromLNX(data)
+	case "g64":
+		return extractFirstPRGFromG64(data)
+	default:
+		return nil, "", fmt.Errorf("unsupported container type: %s", ext)
+	}
+}
+
+// d64FileTypeName returns the short display name for a directory entry's
+// file type byte, as printed in a real C64 directory listing.
+func d64FileTypeName(fileType byte) string {
+	switch fileType {
+	case fileTypeDEL:
+		return "DEL"
+	case fileTypeSEQ:
+		return "SEQ"
+	case fileTypePRG:
+		return "PRG"
+	case fileTypeUSR:
+		return "USR"
+	case fileTypeREL:
+		return "REL"
+	default:
+		return "???"
+	}
+}
+
 // extractFirstPRG extracts the first PRG file from a D64 disk image.
 func extractFirstPRG(d64Data []byte) ([]byte, string, error) {
 	// Validate D64 size.
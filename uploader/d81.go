@@ -0,0 +1,74 @@
+// D81 disk image directory parsing.
+//
+// D81 is the 3.5" disk format: 80 tracks of a constant 40 sectors each
+// (unlike D64's variable zones - see getSectorOffset), with the BAM and
+// directory on track 40 instead of track 18. The on-disk 32-byte
+// directory entry layout is otherwise identical to D64's (see
+// d64.go's parseDirectoryEntry).
+package main
+
+import "fmt"
+
+const (
+	d81Tracks          = 80
+	d81SectorsPerTrack = 40
+
+	// BAM occupies track 40 sectors 0-2; the directory starts right
+	// after it.
+	d81DirectoryTrack  = 40
+	d81DirectorySector = 3
+)
+
+// getD81SectorOffset calculates the byte offset for a given track and
+// sector in a D81 image.
+func getD81SectorOffset(track, sector int) int {
+	if track < 1 || track > d81Tracks || sector < 0 || sector >= d81SectorsPerTrack {
+		return -1
+	}
+	return ((track-1)*d81SectorsPerTrack + sector) * bytesPerSector
+}
+
+// listD81Directory scans all directory sectors of a D81 image and
+// returns every entry, in directory order - the D81 counterpart to
+// d64.go's listDirectory.
+func listD81Directory(d81Data []byte) ([]directoryEntry, error) {
+	var entries []directoryEntry
+
+	currentTrack := d81DirectoryTrack
+	currentSector := d81DirectorySector
+
+	for {
+		offset := getD81SectorOffset(currentTrack, currentSector)
+		if offset < 0 || offset+bytesPerSector > len(d81Data) {
+			break
+		}
+
+		sectorData := d81Data[offset : offset+bytesPerSector]
+		nextTrack := sectorData[0x00]
+		nextSector := sectorData[0x01]
+
+		for i := 0; i < 8; i++ {
+			entryOffset := 0x02 + (i * 32)
+			// The 8th entry runs to the end of the sector, 2 bytes short
+			// of 32; pad it so parseDirectoryEntry accepts it.
+			var raw [32]byte
+			copy(raw[:], sectorData[entryOffset:])
+
+			entry := parseDirectoryEntry(raw[:])
+			if entry != nil && entry.track != 0 {
+				entries = append(entries, *entry)
+			}
+		}
+
+		if nextTrack == 0 {
+			break
+		}
+		currentTrack = int(nextTrack)
+		currentSector = int(nextSector)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no files found in D81 image")
+	}
+	return entries, nil
+}
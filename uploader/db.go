@@ -0,0 +1,136 @@
+// Loading of the JSON game database (see dbgen.go) into a SearchIndex.
+//
+// games.json can run into the hundreds of MB for a full Assembly64 mirror.
+// LoadIndexFromJSON memory-maps the file instead of reading it onto the
+// heap, and streams its "entries" array one record at a time with
+// json.Decoder rather than unmarshaling the whole array into a single
+// []DBEntry slice, so neither the raw JSON nor a full []DBEntry copy is
+// held on the heap; the resulting SearchIndex still holds every entry.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/mmap"
+)
+
+// LoadIndexFromJSON builds a SearchIndex by streaming entries out of a
+// generated JSON database file (see dbgen.go's GenerateCatalogDBs).
+func LoadIndexFromJSON(dbPath, assembly64Path string) (*SearchIndex, error) {
+	reader, err := mmap.Open(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", dbPath, err)
+	}
+	defer reader.Close()
+
+	dec := json.NewDecoder(io.NewSectionReader(reader, 0, int64(reader.Len())))
+
+	if err := skipToEntriesArray(dec); err != nil {
+		return nil, fmt.Errorf("failed to parse database %s: %w", dbPath, err)
+	}
+
+	index := &SearchIndex{
+		ByCategory: make(map[string][]int),
+	}
+
+	for dec.More() {
+		var dbEntry DBEntry
+		if err := dec.Decode(&dbEntry); err != nil {
+			return nil, fmt.Errorf("failed to decode database entry: %w", err)
+		}
+
+		entry := releaseEntryFromDBEntry(dbEntry, assembly64Path)
+		idx := len(index.Entries)
+		index.Entries = append(index.Entries, entry)
+
+		if _, ok := index.ByCategory[entry.CategoryName]; !ok {
+			index.CategoryOrder = append(index.CategoryOrder, entry.CategoryName)
+		}
+		index.ByCategory[entry.CategoryName] = append(index.ByCategory[entry.CategoryName], idx)
+		index.ByCategory["All"] = append(index.ByCategory["All"], idx)
+	}
+
+	// "All" is always first, the rest in first-seen order.
+	index.CategoryOrder = append([]string{"All"}, index.CategoryOrder...)
+
+	return index, nil
+}
+
+// skipToEntriesArray advances dec past the database's leading scalar
+// fields (version, generated, source, totalEntries) to the opening '['
+// of the "entries" array, without ever decoding the array itself.
+func skipToEntriesArray(dec *json.Decoder) error {
+	if _, err := dec.Token(); err != nil { // Opening '{' of the root object.
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if key == "entries" {
+			if _, err := dec.Token(); err != nil { // Opening '[' of the array.
+				return err
+			}
+			return nil
+		}
+
+		// Skip this field's value (a scalar for every other top-level field).
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf(`no "entries" field found`)
+}
+
+// releaseEntryFromDBEntry converts one database record into the
+// ReleaseEntry shape the rest of the program works with, resolving the
+// file's path relative to the Assembly64 collection.
+func releaseEntryFromDBEntry(e DBEntry, assembly64Path string) ReleaseEntry {
+	fullPath := e.PrimaryFile
+	if fullPath != "" && !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(assembly64Path, e.Path, fullPath)
+	}
+
+	return ReleaseEntry{
+		Name:         e.Title,
+		Group:        e.Group,
+		Path:         e.Path,
+		CategoryName: e.Category,
+		FullPath:     fullPath,
+		FileType:     strings.TrimPrefix(strings.ToLower(filepath.Ext(fullPath)), "."),
+		Top200Rank:   derefOr(e.Top200Rank, 0),
+		Crack:        e.Crack,
+		Language:     e.Language,
+		Region:       e.Region,
+		Engine:       e.Engine,
+		Is4k:         e.Is4k,
+		Hash:         primaryFileHash(e),
+	}
+}
+
+// primaryFileHash returns the SHA-256 hash dbgen recorded for e's
+// primary file, or "" if e predates content hashing.
+func primaryFileHash(e DBEntry) string {
+	for _, f := range e.Files {
+		if f.Name == e.PrimaryFile {
+			return f.Hash
+		}
+	}
+	return ""
+}
+
+// derefOr dereferences a possibly-nil pointer, returning def if it's nil.
+func derefOr(p *int, def int) int {
+	if p == nil {
+		return def
+	}
+	return *p
+}
@@ -3,35 +3,47 @@
 package main
 
 import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // DBEntry represents a single entry in our JSON database.
 type DBEntry struct {
-	ID          int       `json:"id"`
-	Category    string    `json:"category"`
-	Title       string    `json:"title"`
-	ReleaseName string    `json:"releaseName"`
-	Group       string    `json:"group"`
-	Top200Rank  *int      `json:"top200Rank,omitempty"`
-	Is4k        bool      `json:"is4k,omitempty"`
-	Path        string    `json:"path"`
-	Files       []DBFile  `json:"files"`
-	PrimaryFile string    `json:"primaryFile"`
-	FileType    string    `json:"fileType"`
+	ID          int        `json:"id"`
+	Category    string     `json:"category"`
+	Title       string     `json:"title"`
+	ReleaseName string     `json:"releaseName"`
+	Group       string     `json:"group"`
+	Top200Rank  *int       `json:"top200Rank,omitempty"`
+	Is4k        bool       `json:"is4k,omitempty"`
+	Path        string     `json:"path"`
+	Files       []DBFile   `json:"files"`
+	PrimaryFile string     `json:"primaryFile"`
+	FileType    string     `json:"fileType"`
 	Crack       *CrackInfo `json:"crack,omitempty"`
-	Language    string    `json:"language,omitempty"`
-	Region      string    `json:"region,omitempty"`
-	Engine      string    `json:"engine,omitempty"`
-	IsPreview   bool      `json:"isPreview,omitempty"`
-	Version     string    `json:"version,omitempty"`
+	Language    string     `json:"language,omitempty"`
+	Region      string     `json:"region,omitempty"`
+	Engine      string     `json:"engine,omitempty"`
+	IsPreview   bool       `json:"isPreview,omitempty"`
+	Version     string     `json:"version,omitempty"`
+
+	// DirModTime is the release folder's mtime (RFC3339) at scan time.
+	// -update compares it against the previous run's value to decide
+	// whether the folder needs rescanning at all.
+	DirModTime string `json:"dirModTime,omitempty"`
 }
 
 // DBFile represents a file within a release.
@@ -39,6 +51,131 @@ type DBFile struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
 	Size int64  `json:"size"`
+
+	// Hash is the file's SHA-256 content hash, hex-encoded. ModTime
+	// (RFC3339) is recorded alongside it so -update can tell a file
+	// hasn't changed from its size+mtime alone, without re-hashing it.
+	Hash    string `json:"hash,omitempty"`
+	ModTime string `json:"modTime,omitempty"`
+
+	// Programs lists the individual programs found inside a
+	// multi-program disk/tape image (D64, D81, T64), parsed from its
+	// CBM DOS directory or tape header - see extractPrograms. Nil for a
+	// single-program format (PRG/CRT) or a disk format dbgen doesn't
+	// parse directories for (G64).
+	Programs []DBProgram `json:"programs,omitempty"`
+}
+
+// DBProgram is one program found inside a disk/tape image's directory,
+// letting a frontend list and pick between the individual programs on a
+// multi-program disk rather than only ever loading DBFile's first one.
+type DBProgram struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"` // PRG, SEQ, USR, REL, or DEL - see d64FileTypeName.
+	Track  int    `json:"track,omitempty"`
+	Sector int    `json:"sector,omitempty"`
+	Blocks int    `json:"blocks"`
+}
+
+// extractPrograms parses path's CBM DOS directory (D64, D81) or tape
+// header (T64) and returns every program it contains. Any other
+// fileType returns (nil, nil) - dbgen only records programs for these
+// formats.
+func extractPrograms(path, fileType string) ([]DBProgram, error) {
+	switch fileType {
+	case "d64":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := listDirectory(data)
+		if err != nil {
+			return nil, err
+		}
+		return programsFromD64Entries(entries), nil
+
+	case "d81":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := listD81Directory(data)
+		if err != nil {
+			return nil, err
+		}
+		return programsFromD64Entries(entries), nil
+
+	case "t64":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := parseT64Directory(data)
+		if err != nil {
+			return nil, err
+		}
+		return programsFromT64Entries(entries), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// programsFromD64Entries converts D64/D81 directory entries (the same
+// on-disk layout - see d64.go's parseDirectoryEntry) into DBProgram
+// records. Names already have PETSCII-to-ASCII conversion and $A0
+// padding stripped by parseDirectoryEntry.
+func programsFromD64Entries(entries []directoryEntry) []DBProgram {
+	programs := make([]DBProgram, 0, len(entries))
+	for _, e := range entries {
+		programs = append(programs, DBProgram{
+			Name:   e.filename,
+			Type:   d64FileTypeName(e.fileType),
+			Track:  int(e.track),
+			Sector: int(e.sector),
+			Blocks: int(e.blockCount),
+		})
+	}
+	return programs
+}
+
+// programsFromT64Entries converts T64 directory entries into DBProgram
+// records, skipping anything other than a normal file entry (T64 also
+// defines memory-snapshot entry types we don't treat as programs). T64
+// has no block count of its own, so Blocks is derived from the load
+// address range the same way a real directory listing would report it.
+func programsFromT64Entries(entries []t64Entry) []DBProgram {
+	programs := make([]DBProgram, 0, len(entries))
+	for _, e := range entries {
+		if e.entryType != t64EntryTypeNormal {
+			continue
+		}
+		size := int(e.endAddr) - int(e.startAddr)
+		if size < 0 {
+			size = 0
+		}
+		programs = append(programs, DBProgram{
+			Name:   e.filename,
+			Type:   "PRG",
+			Blocks: (size + 253) / 254,
+		})
+	}
+	return programs
+}
+
+// hashFile computes path's SHA-256 content hash, hex-encoded.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // CrackInfo contains parsed crack/trainer information.
@@ -50,11 +187,155 @@ type CrackInfo struct {
 
 // Database represents the complete JSON database structure.
 type Database struct {
-	Version      string    `json:"version"`
-	Generated    string    `json:"generated"`
-	Source       string    `json:"source"`
-	TotalEntries int       `json:"totalEntries"`
-	Entries      []DBEntry `json:"entries"`
+	Version       string        `json:"version"`
+	SchemaVersion SchemaVersion `json:"schemaVersion"`
+	Generated     string        `json:"generated"`
+	Source        string        `json:"source"`
+	TotalEntries  int           `json:"totalEntries"`
+	Entries       []DBEntry     `json:"entries"`
+}
+
+// SchemaVersion identifies the shape of a Database JSON document, so a
+// reader knows how to interpret an older file and Migrate knows which
+// steps to apply to bring it up to date.
+type SchemaVersion int
+
+const (
+	// schemaVersion1 is the original schema: DBEntry's Crack/Language/
+	// Region/Engine/IsPreview/Version fields didn't exist yet, and a
+	// document at this version - or with no "schemaVersion" field at
+	// all - carries only the release name for that metadata.
+	schemaVersion1 SchemaVersion = 1
+
+	// schemaVersion2 added DBEntry's structured release-name metadata
+	// fields (Crack, Language, Region, Engine, IsPreview, Version).
+	schemaVersion2 SchemaVersion = 2
+
+	// schemaVersion3 added DBFile.Programs, the disk/tape image's
+	// parsed program listing (see DBProgram).
+	schemaVersion3 SchemaVersion = 3
+
+	// nextSchemaVersion is the version GenerateCatalogDBs writes. Bump it
+	// (and add a schemaVersionN constant plus a migrationSteps entry)
+	// whenever Database/DBEntry/DBFile changes in a way a reader needs
+	// to know about to interpret correctly.
+	nextSchemaVersion = schemaVersion3
+)
+
+// DBIndex is the manifest written alongside shard-by-letter output (see
+// writeShardedDB), listing where each letter's entries ended up so a
+// browser client can lazy-load only the shard for the currently-browsed
+// letter instead of the entire database.
+type DBIndex struct {
+	Version      string         `json:"version"`
+	Generated    string         `json:"generated"`
+	Source       string         `json:"source"`
+	TotalEntries int            `json:"totalEntries"`
+	Shards       []DBIndexShard `json:"shards"`
+}
+
+// DBIndexShard describes one shard file listed in a DBIndex.
+type DBIndexShard struct {
+	Letter  string `json:"letter"`
+	File    string `json:"file"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// ScanConfig limits a category scan to a subset of release folders.
+// Both Include and Exclude are matched with filepath.Match against the
+// release folder's path relative to the category's base subpath (e.g.
+// "A/A-AA/ArkanoidPlus/RGCD/ArkanoidPlus_RGCD" under "Games/CSDB/All").
+// A folder is scanned when it matches at least one Include pattern (all
+// folders match when Include is empty) and no Exclude pattern.
+type ScanConfig struct {
+	Include []string
+	Exclude []string
+}
+
+// matches reports whether relPath passes cfg's include/exclude filters.
+func (cfg ScanConfig) matches(relPath string) bool {
+	if len(cfg.Include) > 0 {
+		included := false
+		for _, pattern := range cfg.Include {
+			if ok, _ := filepath.Match(pattern, relPath); ok {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, pattern := range cfg.Exclude {
+		if ok, _ := filepath.Match(pattern, relPath); ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CategoryMetadata holds the category-specific fields a MetadataParser
+// extracts from a release name, keyed by DBEntry field name. Using a
+// map here (rather than growing DBEntry with one field set per
+// category) lets a new category like "demos" or "musicdisks" contribute
+// its own fields without touching DBEntry.
+type CategoryMetadata map[string]any
+
+// MetadataParser extracts a category's metadata fields from a release
+// folder name - see gameMetadataParser for the "games" category.
+type MetadataParser interface {
+	ParseMetadata(releaseName string) CategoryMetadata
+}
+
+// CategoryScanner registers a release-folder category so a walker can
+// be driven generically instead of hard-coding one category's layout.
+// GenerateCatalogDBs drives every entry in categoryScanners; add a new
+// category here rather than writing it its own generator.
+type CategoryScanner struct {
+	// Name is recorded as DBEntry.Category for every release this
+	// scanner finds.
+	Name string
+	// BaseSubpath is the category's release tree root under the
+	// Assembly64 data directory, e.g. {"Games", "CSDB", "All"}.
+	BaseSubpath []string
+	// Depth is the number of path components below BaseSubpath at
+	// which a release folder lives, e.g. 5 for
+	// Letter/Range/Title/Group/ReleaseName.
+	Depth int
+	// Parser extracts this category's metadata fields from a release
+	// name.
+	Parser MetadataParser
+}
+
+// categoryScanners is the registry of known release-folder categories.
+// Register a new category here rather than copying GenerateCatalogDBs's
+// walker for it.
+var categoryScanners = map[string]CategoryScanner{
+	"games": {
+		Name:        "games",
+		BaseSubpath: []string{"Games", "CSDB", "All"},
+		Depth:       5,
+		Parser:      gameMetadataParser{},
+	},
+}
+
+// gameMetadataParser is the "games" category's MetadataParser,
+// wrapping the existing parseCrackInfo/parseLanguage/parseRegion/
+// parseEngine/isPreview/parseVersion helpers.
+type gameMetadataParser struct{}
+
+func (gameMetadataParser) ParseMetadata(releaseName string) CategoryMetadata {
+	return CategoryMetadata{
+		"crack":     parseCrackInfo(releaseName),
+		"language":  parseLanguage(releaseName),
+		"region":    parseRegion(releaseName),
+		"engine":    parseEngine(releaseName),
+		"isPreview": isPreview(releaseName),
+		"version":   parseVersion(releaseName),
+	}
 }
 
 // Supported file extensions for C64 programs.
@@ -171,15 +452,23 @@ func isPreview(releaseName string) bool {
 	return previewPattern.MatchString(releaseName)
 }
 
-// scanReleaseFolder scans a release folder and returns file information.
-func scanReleaseFolder(folderPath string) ([]DBFile, string, string) {
-	var files []DBFile
-	var primaryFile string
-	var fileType string
+// scanReleaseFolder scans a release folder and returns its file
+// information plus whether anything about it changed relative to prev
+// (the matching entry from a previous dbgen run, or nil on a fresh
+// generate). A file is only re-hashed when it's new or its size/mtime
+// differ from prev's record of it - see hashFile's callers below - so
+// -update only pays hashing cost for what actually changed.
+func scanReleaseFolder(folderPath string, prev *DBEntry, verify bool) (files []DBFile, primaryFile, fileType string, changed bool) {
+	prevByName := make(map[string]DBFile)
+	if prev != nil {
+		for _, f := range prev.Files {
+			prevByName[f.Name] = f
+		}
+	}
 
 	entries, err := os.ReadDir(folderPath)
 	if err != nil {
-		return nil, "", ""
+		return nil, "", "", prev != nil
 	}
 
 	// Collect all supported files.
@@ -198,15 +487,51 @@ func scanReleaseFolder(folderPath string) ([]DBFile, string, string) {
 			continue
 		}
 
-		files = append(files, DBFile{
-			Name: entry.Name(),
-			Type: strings.ToLower(strings.TrimPrefix(ext, ".")),
-			Size: info.Size(),
-		})
+		file := DBFile{
+			Name:    entry.Name(),
+			Type:    strings.ToLower(strings.TrimPrefix(ext, ".")),
+			Size:    info.Size(),
+			ModTime: info.ModTime().UTC().Format(time.RFC3339),
+		}
+
+		prevFile, known := prevByName[file.Name]
+		unchanged := known && !verify && prevFile.Size == file.Size && prevFile.ModTime == file.ModTime
+		if unchanged {
+			file.Hash = prevFile.Hash
+			file.Programs = prevFile.Programs
+		} else {
+			hash, err := hashFile(filepath.Join(folderPath, entry.Name()))
+			if err != nil {
+				slog.Warn("Failed to hash file", "path", filepath.Join(folderPath, entry.Name()), "error", err)
+			} else {
+				file.Hash = hash
+			}
+			if !known || prevFile.Hash != file.Hash {
+				changed = true
+			}
+
+			programs, err := extractPrograms(filepath.Join(folderPath, entry.Name()), file.Type)
+			if err != nil {
+				slog.Warn("Failed to list disk image directory", "path", filepath.Join(folderPath, entry.Name()), "error", err)
+			} else {
+				file.Programs = programs
+			}
+		}
+
+		files = append(files, file)
+		delete(prevByName, file.Name)
+	}
+
+	// Anything left in prevByName was removed since the last run.
+	if len(prevByName) > 0 {
+		changed = true
+	}
+	if prev == nil {
+		changed = true
 	}
 
 	if len(files) == 0 {
-		return nil, "", ""
+		return nil, "", "", changed
 	}
 
 	// Select primary file by priority.
@@ -229,7 +554,83 @@ func scanReleaseFolder(folderPath string) ([]DBFile, string, string) {
 		fileType = files[0].Type
 	}
 
-	return files, primaryFile, fileType
+	return files, primaryFile, fileType, changed
+}
+
+// scanJob is one release folder queued for a generateCategoryDB worker
+// to inspect - everything scanCategoryEntry needs that the
+// single-threaded directory walk in generateCategoryDB already had to
+// stat anyway.
+type scanJob struct {
+	path        string
+	relPath     string
+	title       string
+	group       string
+	releaseName string
+	prev        *DBEntry
+	dirModTime  string
+}
+
+// scanOutcome is one scanJob's result. skip is set when the folder
+// turned out to have no supported files (and so contributes no entry);
+// added and changed mirror scanReleaseFolder's changed return and
+// whether job.prev existed at all, for the diff report.
+type scanOutcome struct {
+	relPath string
+	entry   DBEntry
+	added   bool
+	changed bool
+	skip    bool
+}
+
+// scanCategoryEntry turns one scanJob into its scanOutcome: a reused
+// copy of job.prev when the release folder's mtime hasn't changed since
+// the previous run (without even opening it to stat individual files),
+// or a freshly scanned entry otherwise. Called from a generateCategoryDB
+// worker goroutine, so it must not touch anything another concurrent
+// call also writes to.
+func scanCategoryEntry(job scanJob, scanner CategoryScanner, top200Map map[string]int, fourKMap map[string]bool) scanOutcome {
+	if job.prev != nil && job.prev.DirModTime != "" && job.prev.DirModTime == job.dirModTime {
+		return scanOutcome{relPath: job.relPath, entry: *job.prev}
+	}
+
+	files, primaryFile, fileType, changed := scanReleaseFolder(job.path, job.prev, false)
+	if len(files) == 0 {
+		return scanOutcome{relPath: job.relPath, skip: true}
+	}
+
+	// Check Top200 rank.
+	var top200Rank *int
+	if rank, ok := top200Map[strings.ToLower(job.title)]; ok {
+		top200Rank = &rank
+	}
+
+	// Check if 4k game.
+	is4k := fourKMap[strings.ToLower(job.title)]
+
+	// Parse release name metadata.
+	meta := scanner.Parser.ParseMetadata(job.releaseName)
+	entry := DBEntry{
+		Category:    scanner.Name,
+		Title:       job.title,
+		ReleaseName: job.releaseName,
+		Group:       job.group,
+		Top200Rank:  top200Rank,
+		Is4k:        is4k,
+		Path:        job.relPath,
+		Files:       files,
+		PrimaryFile: primaryFile,
+		FileType:    fileType,
+		Crack:       meta["crack"].(*CrackInfo),
+		Language:    meta["language"].(string),
+		Region:      meta["region"].(string),
+		Engine:      meta["engine"].(string),
+		IsPreview:   meta["isPreview"].(bool),
+		Version:     meta["version"].(string),
+		DirModTime:  job.dirModTime,
+	}
+
+	return scanOutcome{relPath: job.relPath, entry: entry, changed: changed, added: job.prev == nil}
 }
 
 // buildTop200Map scans Top200 folder and returns a map of title -> rank.
@@ -290,130 +691,612 @@ func build4kMap(basePath string) map[string]bool {
 	return fourKMap
 }
 
-// GenerateGamesDB generates the games.json database file.
-func GenerateGamesDB(basePath, outputPath string) error {
-	fmt.Println("Scanning Games/CSDB/All...")
+// dbDiff reports what changed between an incremental dbgen run and the
+// database it started from, keyed by DBEntry.Path.
+type dbDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// empty reports whether the diff found nothing to report.
+func (d dbDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// loadPreviousDB reads an existing database written by a prior dbgen run,
+// for -update's incremental rescan and -verify's hash check. A missing
+// file is not an error - it just means there's nothing to reuse or
+// verify against.
+func loadPreviousDB(path string) (*Database, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
 
-	// Build metadata maps from Top200 and 4k folders.
-	fmt.Println("Building Top200 rank map...")
-	top200Map := buildTop200Map(basePath)
-	fmt.Printf("  Found %d Top200 entries\n", len(top200Map))
+	db, err := Migrate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+	return &db, nil
+}
 
-	fmt.Println("Building 4k games map...")
-	fourKMap := build4kMap(basePath)
-	fmt.Printf("  Found %d 4k entries\n", len(fourKMap))
+// migrationStep upgrades a raw database document by exactly one schema
+// version and returns it; Migrate re-tags the result with the new
+// version number after calling it.
+type migrationStep func(doc map[string]any) (map[string]any, error)
+
+// migrationSteps maps a SchemaVersion to the step that upgrades a
+// document from it to the next version. Migrate applies them in order
+// until the document reaches nextSchemaVersion.
+var migrationSteps = map[SchemaVersion]migrationStep{
+	schemaVersion1: migrateV1ToV2,
+	schemaVersion2: migrateV2ToV3,
+}
 
-	// Scan the main Games/CSDB/All directory.
-	allPath := filepath.Join(basePath, "Games", "CSDB", "All")
+// Migrate parses old (a Database JSON document of any known
+// SchemaVersion, including an unversioned file predating the field) and
+// returns it upgraded to nextSchemaVersion.
+func Migrate(old []byte) (Database, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(old, &doc); err != nil {
+		return Database{}, fmt.Errorf("failed to parse database: %w", err)
+	}
 
-	var entries []DBEntry
-	entryID := 1
+	version := schemaVersion1
+	if v, ok := doc["schemaVersion"].(float64); ok {
+		version = SchemaVersion(v)
+	}
 
-	// Walk through: Letter / Range / Title / Group / ReleaseName
-	err := filepath.WalkDir(allPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
+	for version < nextSchemaVersion {
+		step, ok := migrationSteps[version]
+		if !ok {
+			return Database{}, fmt.Errorf("no migration step from schema version %d", version)
 		}
 
-		if !d.IsDir() {
-			return nil
+		var err error
+		doc, err = step(doc)
+		if err != nil {
+			return Database{}, fmt.Errorf("migrating from schema version %d: %w", version, err)
 		}
+		version++
+		doc["schemaVersion"] = float64(version)
+	}
 
-		// Get relative path from All directory.
-		rel, _ := filepath.Rel(allPath, path)
-		if rel == "." {
-			return nil
+	reencoded, err := json.Marshal(doc)
+	if err != nil {
+		return Database{}, fmt.Errorf("failed to re-encode migrated database: %w", err)
+	}
+
+	var db Database
+	if err := json.Unmarshal(reencoded, &db); err != nil {
+		return Database{}, fmt.Errorf("failed to decode migrated database: %w", err)
+	}
+	return db, nil
+}
+
+// migrateV1ToV2 backfills every entry's Crack/Language/Region/Engine/
+// IsPreview/Version fields (added in schemaVersion2) from its
+// releaseName, for entries that don't already have them.
+func migrateV1ToV2(doc map[string]any) (map[string]any, error) {
+	entries, _ := doc["entries"].([]any)
+	for _, raw := range entries {
+		entry, ok := raw.(map[string]any)
+		if !ok {
+			continue
 		}
 
-		parts := strings.Split(rel, string(os.PathSeparator))
+		releaseName, _ := entry["releaseName"].(string)
+		if releaseName == "" {
+			continue
+		}
 
-		// We want release folders at level 5: Letter/Range/Title/Group/ReleaseName
-		if len(parts) != 5 {
-			return nil
+		meta := gameMetadataParser{}.ParseMetadata(releaseName)
+		for _, field := range []string{"crack", "language", "region", "engine", "isPreview", "version"} {
+			if _, ok := entry[field]; ok {
+				continue
+			}
+			if field == "crack" {
+				if crack, ok := meta["crack"].(*CrackInfo); ok && crack != nil {
+					entry["crack"] = crack
+				}
+				continue
+			}
+			entry[field] = meta[field]
 		}
+	}
+	return doc, nil
+}
 
-		// Extract metadata from path.
-		title := parts[2]       // Title folder
-		group := parts[3]       // Group folder
-		releaseName := parts[4] // Release name folder
+// migrateV2ToV3 accounts for DBFile.Programs (added in schemaVersion3).
+// Programs itself isn't backfilled here - that requires re-reading the
+// actual disk/tape images, which a rescan (-update or -merge) does for
+// any folder it touches; Programs is omitempty, so an old file missing
+// it decodes the same as one whose images simply had none.
+func migrateV2ToV3(doc map[string]any) (map[string]any, error) {
+	return doc, nil
+}
 
-		// Scan the release folder for files.
-		files, primaryFile, fileType := scanReleaseFolder(path)
-		if len(files) == 0 {
-			return nil
+// GenerateCatalogDBs generates one database file per category registered
+// in categoryScanners, driving the same walk-and-write pass for each in
+// turn. outputPath names the "games" category's file exactly (preserving
+// the -output flag's existing meaning, since "games" is still the only
+// registered category); any other registered category writes alongside
+// it as "<stem>-<category><ext>", stem and ext being outputPath's base
+// name with its ".json"/".json.gz" suffix split off (see
+// categoryOutputPath). mergePath, when non-empty, follows the same
+// per-category naming. update, shardByLetter, cfg and jobs apply to
+// every category scanned - see generateCategoryDB for what each does.
+func GenerateCatalogDBs(basePath, outputPath string, update bool, mergePath string, shardByLetter bool, cfg ScanConfig, jobs int) error {
+	names := make([]string, 0, len(categoryScanners))
+	for name := range categoryScanners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		scanner := categoryScanners[name]
+		categoryOutput := categoryOutputPath(outputPath, name)
+		categoryMerge := mergePath
+		if categoryMerge != "" {
+			categoryMerge = categoryOutputPath(mergePath, name)
+		}
+		if err := generateCategoryDB(basePath, scanner, categoryOutput, update, categoryMerge, shardByLetter, cfg, jobs); err != nil {
+			return fmt.Errorf("generating %s database: %w", name, err)
 		}
+	}
+
+	return nil
+}
+
+// categoryOutputPath derives category's output path from base, the way
+// writeShardedDB derives a shard's path from its own outputPath: base's
+// ".json"/".json.gz" suffix is split into stem and ext, and a non-"games"
+// category is inserted between them as "<stem>-<category><ext>". The
+// "games" category keeps base unchanged, since it's the path the caller
+// passed in via -output/-merge directly. An empty base (mergePath left
+// unset) stays empty for every category.
+func categoryOutputPath(base, category string) string {
+	if base == "" || category == "games" {
+		return base
+	}
+	ext := ".json"
+	trimmed := strings.TrimSuffix(base, ".gz")
+	if trimmed != base {
+		ext = ".json.gz"
+	}
+	stem := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(base), ".gz"), ".json")
+	return filepath.Join(filepath.Dir(base), stem+"-"+category+ext)
+}
 
-		// Build the relative path from assembly64 root.
-		relPath := filepath.Join("Games", "CSDB", "All", rel)
+// generateCategoryDB generates scanner's database file at outputPath.
+// When update is true and outputPath already exists, a release folder
+// whose mtime hasn't changed since that run is reused verbatim rather
+// than rescanned, and within a folder that does need rescanning, a file
+// is only re-hashed if its size or mtime changed (see
+// scanReleaseFolder). A diff report of added/removed/changed entries is
+// written alongside outputPath as "<outputPath>.diff.json".
+//
+// The database itself is streamed straight to disk rather than built up
+// as one giant byte slice (see writeJSONFile), so peak memory stays
+// bounded on a full CSDB scan. outputPath ending in ".json.gz" writes a
+// gzip-compressed file; shardByLetter instead writes one JSON file per
+// first-letter shard plus a "<stem>-index.json" manifest (see
+// writeShardedDB), so a browser client can lazy-load just one letter.
+// cfg restricts the scan to release folders matching its Include/Exclude
+// globs (see ScanConfig); the zero value scans everything, as before.
+// mergePath, when non-empty, is used as the previous-run database
+// instead of outputPath (which may not exist yet, or may be a fresh
+// location the caller doesn't want clobbered until the merge is
+// verified) and implies the same incremental behavior as update. jobs
+// release folders are scanned concurrently by a worker pool (see
+// scanJob/scanCategoryEntry); pass 1 to scan single-threaded.
+func generateCategoryDB(basePath string, scanner CategoryScanner, outputPath string, update bool, mergePath string, shardByLetter bool, cfg ScanConfig, jobs int) error {
+	fmt.Printf("Scanning %s...\n", filepath.Join(scanner.BaseSubpath...))
+
+	prevSource := outputPath
+	if mergePath != "" {
+		prevSource = mergePath
+		update = true
+	}
 
-		// Check Top200 rank.
-		var top200Rank *int
-		if rank, ok := top200Map[strings.ToLower(title)]; ok {
-			top200Rank = &rank
+	var prevByPath map[string]DBEntry
+	if update {
+		prevDB, err := loadPreviousDB(prevSource)
+		if err != nil {
+			return err
 		}
+		if prevDB != nil {
+			prevByPath = make(map[string]DBEntry, len(prevDB.Entries))
+			for _, e := range prevDB.Entries {
+				prevByPath[e.Path] = e
+			}
+			fmt.Printf("Loaded previous database: %d entries\n", len(prevDB.Entries))
+		}
+	}
+
+	// Top200Rank/Is4k are games-specific DBEntry fields, sourced from
+	// folders that only exist under Games/CSDB; every other category
+	// leaves both maps empty.
+	top200Map := map[string]int{}
+	fourKMap := map[string]bool{}
+	if scanner.Name == "games" {
+		fmt.Println("Building Top200 rank map...")
+		top200Map = buildTop200Map(basePath)
+		fmt.Printf("  Found %d Top200 entries\n", len(top200Map))
+
+		fmt.Println("Building 4k games map...")
+		fourKMap = build4kMap(basePath)
+		fmt.Printf("  Found %d 4k entries\n", len(fourKMap))
+	}
+
+	// Scan the category's release tree.
+	allPath := filepath.Join(append([]string{basePath}, scanner.BaseSubpath...)...)
+
+	diff := dbDiff{}
+	visited := make(map[string]bool)
+
+	if jobs < 1 {
+		jobs = 1
+	}
+	jobCh := make(chan scanJob, jobs*4)
+	outcomeCh := make(chan scanOutcome, jobs*4)
+
+	// Walk through: Letter / Range / Title / Group / ReleaseName,
+	// enqueuing a scanJob for every release folder onto jobCh. This
+	// goroutine only stats directories and does no file I/O itself, so
+	// it stays well ahead of the worker pool below.
+	var walkErr error
+	go func() {
+		defer close(jobCh)
+		walkErr = filepath.WalkDir(allPath, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+
+			if !d.IsDir() {
+				return nil
+			}
+
+			// Get relative path from All directory.
+			rel, _ := filepath.Rel(allPath, path)
+			if rel == "." {
+				return nil
+			}
+
+			parts := strings.Split(rel, string(os.PathSeparator))
+
+			// We want release folders at level 5: Letter/Range/Title/Group/ReleaseName
+			if len(parts) != scanner.Depth {
+				return nil
+			}
+
+			if !cfg.matches(rel) {
+				return nil
+			}
+
+			// Extract metadata from path.
+			title := parts[2]       // Title folder
+			group := parts[3]       // Group folder
+			releaseName := parts[4] // Release name folder
 
-		// Check if 4k game.
-		is4k := fourKMap[strings.ToLower(title)]
+			// Build the relative path from assembly64 root.
+			relPath := filepath.Join(append(append([]string{}, scanner.BaseSubpath...), rel)...)
+
+			var prev *DBEntry
+			if p, ok := prevByPath[relPath]; ok {
+				prev = &p
+				visited[relPath] = true
+			}
+
+			dirModTime := ""
+			if info, err := d.Info(); err == nil {
+				dirModTime = info.ModTime().UTC().Format(time.RFC3339)
+			}
 
-		// Parse release name metadata.
-		entry := DBEntry{
-			ID:          entryID,
-			Category:    "games",
-			Title:       title,
-			ReleaseName: releaseName,
-			Group:       group,
-			Top200Rank:  top200Rank,
-			Is4k:        is4k,
-			Path:        relPath,
-			Files:       files,
-			PrimaryFile: primaryFile,
-			FileType:    fileType,
-			Crack:       parseCrackInfo(releaseName),
-			Language:    parseLanguage(releaseName),
-			Region:      parseRegion(releaseName),
-			Engine:      parseEngine(releaseName),
-			IsPreview:   isPreview(releaseName),
-			Version:     parseVersion(releaseName),
+			jobCh <- scanJob{
+				path:        path,
+				relPath:     relPath,
+				title:       title,
+				group:       group,
+				releaseName: releaseName,
+				prev:        prev,
+				dirModTime:  dirModTime,
+			}
+			return nil
+		})
+	}()
+
+	// A pool of jobs workers performs the actual scanReleaseFolder/
+	// metadata-parsing work for each release folder concurrently -
+	// that's where the walk spends most of its wall time (see
+	// scanCategoryEntry), not in the single-threaded directory walk
+	// above.
+	var workers sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobCh {
+				outcomeCh <- scanCategoryEntry(job, scanner, top200Map, fourKMap)
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(outcomeCh)
+	}()
+
+	// Collect every outcome, then assign IDs in deterministic
+	// sorted-path order so successive runs produce byte-identical
+	// output regardless of which worker finished first.
+	var outcomes []scanOutcome
+	for outcome := range outcomeCh {
+		if outcome.skip {
+			continue
 		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	if walkErr != nil {
+		return fmt.Errorf("failed to scan directory: %w", walkErr)
+	}
+
+	sort.Slice(outcomes, func(i, j int) bool { return outcomes[i].relPath < outcomes[j].relPath })
 
+	entries := make([]DBEntry, 0, len(outcomes))
+	for i, outcome := range outcomes {
+		entry := outcome.entry
+		entry.ID = i + 1
 		entries = append(entries, entry)
-		entryID++
 
-		if entryID%10000 == 0 {
-			fmt.Printf("  Processed %d entries...\n", entryID-1)
+		if outcome.changed {
+			if outcome.added {
+				diff.Added = append(diff.Added, outcome.relPath)
+			} else {
+				diff.Changed = append(diff.Changed, outcome.relPath)
+			}
 		}
 
-		return nil
-	})
+		if (i+1)%10000 == 0 {
+			fmt.Printf("  Processed %d entries...\n", i+1)
+		}
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to scan directory: %w", err)
+	for relPath := range prevByPath {
+		if !visited[relPath] {
+			diff.Removed = append(diff.Removed, relPath)
+		}
 	}
 
 	fmt.Printf("  Total entries: %d\n", len(entries))
 
+	if update {
+		if err := writeDiffReport(outputPath, diff); err != nil {
+			return err
+		}
+	}
+
 	// Build database structure.
 	db := Database{
-		Version:      "1.0",
-		Generated:    time.Now().UTC().Format(time.RFC3339),
-		Source:       "csdb",
-		TotalEntries: len(entries),
-		Entries:      entries,
+		Version:       fmt.Sprintf("%d.0", nextSchemaVersion),
+		SchemaVersion: nextSchemaVersion,
+		Generated:     time.Now().UTC().Format(time.RFC3339),
+		Source:        "csdb",
+		TotalEntries:  len(entries),
+		Entries:       entries,
 	}
 
-	// Write JSON file.
+	// Write the database, either as a single (optionally gzipped) file
+	// or as letter shards plus a manifest.
 	fmt.Printf("Writing %s...\n", outputPath)
 
-	jsonData, err := json.Marshal(db)
+	if shardByLetter {
+		if err := writeShardedDB(db, outputPath); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	size, err := writeJSONFile(db, outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return err
 	}
 
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	fmt.Printf("Done! Generated %s (%d bytes, %d entries)\n", outputPath, size, len(entries))
+
+	return nil
+}
+
+// writeJSONFile JSON-encodes v directly to outputPath using a streaming
+// json.Encoder, rather than building the whole document in memory first
+// like json.Marshal would - this keeps peak memory bounded for a
+// hundreds-of-MB database. outputPath ending in ".json.gz" wraps the
+// output in a gzip writer. It returns the number of bytes written to
+// disk (the compressed size, when gzipped).
+func writeJSONFile(v any, outputPath string) (int64, error) {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", outputPath, err)
+	}
+	defer f.Close()
+
+	var w io.Writer = f
+	var gz *gzip.Writer
+	if strings.HasSuffix(outputPath, ".json.gz") {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return 0, fmt.Errorf("failed to encode %s: %w", outputPath, err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return 0, fmt.Errorf("failed to close gzip writer for %s: %w", outputPath, err)
+		}
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", outputPath, err)
+	}
+	return info.Size(), nil
+}
+
+// shardLetter returns entry's shard key for shard-by-letter mode: the
+// lowercased first letter of its Title, or "0" for a title that doesn't
+// start with a letter.
+func shardLetter(entry DBEntry) string {
+	if entry.Title == "" {
+		return "0"
+	}
+	r := strings.ToLower(entry.Title)[0]
+	if r < 'a' || r > 'z' {
+		return "0"
+	}
+	return string(r)
+}
+
+// writeShardedDB splits db's entries by shardLetter and writes one JSON
+// file per letter named "<stem>-<letter>.json" alongside outputPath
+// (stem being outputPath's base name with its ".json"/".json.gz" suffix
+// stripped), plus a "<stem>-index.json" manifest (see DBIndex) pointing
+// at them.
+func writeShardedDB(db Database, outputPath string) error {
+	dir := filepath.Dir(outputPath)
+	stem := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(outputPath), ".gz"), ".json")
+
+	byLetter := make(map[string][]DBEntry)
+	for _, entry := range db.Entries {
+		letter := shardLetter(entry)
+		byLetter[letter] = append(byLetter[letter], entry)
+	}
+
+	letters := make([]string, 0, len(byLetter))
+	for letter := range byLetter {
+		letters = append(letters, letter)
+	}
+	sort.Strings(letters)
+
+	index := DBIndex{
+		Version:      db.Version,
+		Generated:    db.Generated,
+		Source:       db.Source,
+		TotalEntries: db.TotalEntries,
+	}
+
+	for _, letter := range letters {
+		shardEntries := byLetter[letter]
+		shardName := fmt.Sprintf("%s-%s.json", stem, letter)
+		shardDB := Database{
+			Version:       db.Version,
+			SchemaVersion: db.SchemaVersion,
+			Generated:     db.Generated,
+			Source:        db.Source,
+			TotalEntries:  len(shardEntries),
+			Entries:       shardEntries,
+		}
+
+		size, err := writeJSONFile(shardDB, filepath.Join(dir, shardName))
+		if err != nil {
+			return err
+		}
+
+		index.Shards = append(index.Shards, DBIndexShard{
+			Letter:  letter,
+			File:    shardName,
+			Entries: len(shardEntries),
+			Bytes:   size,
+		})
+	}
+
+	indexPath := filepath.Join(dir, stem+"-index.json")
+	if _, err := writeJSONFile(index, indexPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Done! Generated %d shards + %s (%d entries)\n", len(index.Shards), indexPath, db.TotalEntries)
+
+	return nil
+}
+
+// diffReportPath returns the path -update writes its diff report to,
+// alongside the database itself.
+func diffReportPath(outputPath string) string {
+	return outputPath + ".diff.json"
+}
+
+// writeDiffReport writes diff to diffReportPath(outputPath) and prints a
+// one-line-per-entry summary, so an -update run's effect is visible
+// without having to diff two full databases by hand.
+func writeDiffReport(outputPath string, diff dbDiff) error {
+	if diff.empty() {
+		fmt.Println("No changes since previous database")
+		return nil
+	}
+
+	fmt.Printf("Changes since previous database: %d added, %d removed, %d changed\n",
+		len(diff.Added), len(diff.Removed), len(diff.Changed))
+	for _, p := range diff.Added {
+		fmt.Printf("  + %s\n", p)
+	}
+	for _, p := range diff.Removed {
+		fmt.Printf("  - %s\n", p)
+	}
+	for _, p := range diff.Changed {
+		fmt.Printf("  ~ %s\n", p)
 	}
 
-	fmt.Printf("Done! Generated %s (%d bytes, %d entries)\n", outputPath, len(jsonData), len(entries))
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode diff report: %w", err)
+	}
 
+	path := diffReportPath(outputPath)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write diff report: %w", err)
+	}
+	fmt.Printf("Diff report written to %s\n", path)
 	return nil
 }
+
+// VerifyGamesDB re-hashes every file referenced by dbPath's entries and
+// compares the result against the hash recorded at generation time,
+// to catch bit-rot in the Assembly64 collection. It returns the list of
+// "path/filename: recorded vs actual" mismatches found; a non-nil error
+// means verification itself couldn't run (missing/corrupt database),
+// not that entries failed verification.
+func VerifyGamesDB(basePath, dbPath string) ([]string, error) {
+	db, err := loadPreviousDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if db == nil {
+		return nil, fmt.Errorf("database %s does not exist", dbPath)
+	}
+
+	var mismatches []string
+	for _, entry := range db.Entries {
+		dir := filepath.Join(basePath, entry.Path)
+		for _, file := range entry.Files {
+			if file.Hash == "" {
+				continue
+			}
+
+			actual, err := hashFile(filepath.Join(dir, file.Name))
+			if err != nil {
+				mismatches = append(mismatches, fmt.Sprintf("%s/%s: %v", entry.Path, file.Name, err))
+				continue
+			}
+			if actual != file.Hash {
+				mismatches = append(mismatches, fmt.Sprintf("%s/%s: recorded %s, actual %s", entry.Path, file.Name, file.Hash, actual))
+			}
+		}
+	}
+
+	return mismatches, nil
+}
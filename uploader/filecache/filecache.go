@@ -0,0 +1,194 @@
+// Package filecache provides a block-based LRU cache in front of large
+// file reads, so repeated RUN/load requests for the same D64/G64 image
+// don't re-read it from disk on every request.
+package filecache
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// BlockSize is the unit of caching; large disk images are split into
+// fixed-size blocks so a single cache can hold many files.
+const BlockSize = 256 * 1024 // 256 KB
+
+// DefaultGlobalBudget is the default total memory budget across all cached files.
+const DefaultGlobalBudget = 256 * 1024 * 1024 // 256 MB
+
+// blockKey identifies a single block within a file.
+type blockKey struct {
+	path  string
+	block int
+}
+
+// CacheBlock holds the bytes for a single block.
+type CacheBlock struct {
+	data []byte
+}
+
+// DataRequestFunc reads length bytes at offset from the backing file.
+type DataRequestFunc func(offset int64, length int) ([]byte, error)
+
+// Cache is a shared block cache backing any number of CachedFiles.
+// A global memory budget bounds the total number of cached blocks.
+type Cache struct {
+	blocks *lru.Cache[blockKey, *CacheBlock]
+}
+
+// NewCache creates a block cache with the given total memory budget.
+func NewCache(budgetBytes int) (*Cache, error) {
+	if budgetBytes <= 0 {
+		budgetBytes = DefaultGlobalBudget
+	}
+	maxBlocks := budgetBytes / BlockSize
+	if maxBlocks < 1 {
+		maxBlocks = 1
+	}
+	blocks, err := lru.New[blockKey, *CacheBlock](maxBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("creating LRU cache: %w", err)
+	}
+	return &Cache{blocks: blocks}, nil
+}
+
+// CachedFile exposes a single file as an io.ReaderAt backed by the shared
+// block cache, fetching missing blocks via dataRequest.
+type CachedFile struct {
+	cache       *Cache
+	path        string
+	size        int64
+	perFileCap  int // Maximum blocks this file may occupy (0 = unbounded).
+	dataRequest DataRequestFunc
+
+	mu     sync.Mutex
+	locks  map[int]*sync.Mutex // Per-block locks so concurrent reads coalesce.
+	ownBlk map[int]bool        // Blocks belonging to this file, for the per-file cap.
+}
+
+// NewCachedFile wraps path as a cached io.ReaderAt. size is the total file
+// size, perFileCapBytes bounds how much of the shared cache this file may
+// occupy (0 means no per-file cap beyond the cache's global budget).
+func NewCachedFile(cache *Cache, path string, size int64, perFileCapBytes int, dataRequest DataRequestFunc) *CachedFile {
+	perFileCap := 0
+	if perFileCapBytes > 0 {
+		perFileCap = perFileCapBytes / BlockSize
+		if perFileCap < 1 {
+			perFileCap = 1
+		}
+	}
+	return &CachedFile{
+		cache:       cache,
+		path:        path,
+		size:        size,
+		perFileCap:  perFileCap,
+		dataRequest: dataRequest,
+		locks:       make(map[int]*sync.Mutex),
+		ownBlk:      make(map[int]bool),
+	}
+}
+
+// blockLock returns (creating if needed) the lock guarding a single block,
+// so concurrent requests for the same block coalesce into one disk read.
+func (f *CachedFile) blockLock(block int) *sync.Mutex {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	l, ok := f.locks[block]
+	if !ok {
+		l = &sync.Mutex{}
+		f.locks[block] = l
+	}
+	return l
+}
+
+// fetchBlock returns the cached block, reading it from disk on a miss.
+func (f *CachedFile) fetchBlock(block int) (*CacheBlock, error) {
+	key := blockKey{path: f.path, block: block}
+
+	if cb, ok := f.cache.blocks.Get(key); ok {
+		return cb, nil
+	}
+
+	lock := f.blockLock(block)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Another goroutine may have filled it in while we waited for the lock.
+	if cb, ok := f.cache.blocks.Get(key); ok {
+		return cb, nil
+	}
+
+	offset := int64(block) * BlockSize
+	length := BlockSize
+	if remaining := f.size - offset; int64(length) > remaining {
+		length = int(remaining)
+	}
+
+	data, err := f.dataRequest(offset, length)
+	if err != nil {
+		return nil, fmt.Errorf("reading block %d of %s: %w", block, f.path, err)
+	}
+
+	cb := &CacheBlock{data: data}
+	f.cache.blocks.Add(key, cb)
+
+	f.mu.Lock()
+	f.ownBlk[block] = true
+	if f.perFileCap > 0 && len(f.ownBlk) > f.perFileCap {
+		f.evictOldestOwnBlockLocked()
+	}
+	f.mu.Unlock()
+
+	return cb, nil
+}
+
+// evictOldestOwnBlockLocked drops one of this file's blocks from the shared
+// cache to honor the per-file budget cap. f.mu must be held.
+func (f *CachedFile) evictOldestOwnBlockLocked() {
+	for block := range f.ownBlk {
+		f.cache.blocks.Remove(blockKey{path: f.path, block: block})
+		delete(f.ownBlk, block)
+		return
+	}
+}
+
+// ReadAt implements io.ReaderAt, satisfying reads from cached blocks and
+// fetching on demand across block boundaries.
+func (f *CachedFile) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if off >= f.size {
+		return 0, io.EOF
+	}
+
+	total := 0
+	for total < len(p) && off+int64(total) < f.size {
+		pos := off + int64(total)
+		block := int(pos / BlockSize)
+		blockOffset := int(pos % BlockSize)
+
+		cb, err := f.fetchBlock(block)
+		if err != nil {
+			return total, err
+		}
+
+		n := copy(p[total:], cb.data[blockOffset:])
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+
+	if total < len(p) {
+		return total, io.EOF
+	}
+	return total, nil
+}
+
+// Size returns the total size of the backing file.
+func (f *CachedFile) Size() int64 {
+	return f.size
+}
@@ -0,0 +1,69 @@
+// Fuzzy matching for the TUI search box, in the spirit of sahilm/fuzzy
+// and fzf's smart-case substring scoring.
+package main
+
+import "strings"
+
+// Matcher scores a query against a single candidate string. It returns
+// the rune indices within target that satisfied the query (for
+// highlighting) and whether the query matched at all. A small interface
+// rather than a bare function so callers can swap in a fake
+// implementation.
+type Matcher interface {
+	Match(query, target string) (score int, positions []int, ok bool)
+}
+
+// fuzzyMatcher scores a query as a (not necessarily contiguous)
+// subsequence of target: every rune of query must appear in target in
+// order. Contiguous runs and a match at the very start of target score
+// higher, the same spirit as fzf's ranking.
+//
+// Smart-case: a query that is entirely lowercase matches
+// case-insensitively; a query containing any uppercase rune forces
+// case-sensitive matching.
+type fuzzyMatcher struct{}
+
+func (fuzzyMatcher) Match(query, target string) (int, []int, bool) {
+	if query == "" {
+		return 0, nil, true
+	}
+
+	q := []rune(query)
+	t := []rune(target)
+	if strings.ToLower(query) == query {
+		q = []rune(strings.ToLower(query))
+		t = []rune(strings.ToLower(target))
+	}
+
+	positions := make([]int, 0, len(q))
+	score := 0
+	prev := -2
+	qi := 0
+
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		positions = append(positions, ti)
+		if prev == ti-1 {
+			score += 15 // Contiguous run.
+		} else {
+			score += 10
+		}
+		if ti == 0 {
+			score += 5 // Match at the very start of the string.
+		}
+		prev = ti
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, nil, false
+	}
+
+	// Prefer shorter, more specific targets over long ones with the same hits.
+	score -= len(t) / 10
+
+	return score, positions, true
+}
@@ -0,0 +1,203 @@
+// G64 GCR disk image parsing and PRG extraction.
+//
+// A G64 stores each track's raw GCR bitstream (the same encoding the 1541
+// drive writes to magnetic media) rather than decoded sector bytes. To
+// reuse the existing D64 directory/extraction code, decodeG64ToD64
+// GCR-decodes every track's header and data blocks and rebuilds a plain
+// D64-layout byte buffer from them.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	g64MagicSize    = 8
+	g64HeaderSize   = 12
+	g64TrackEntries = 84 // Half-tracks 1-84; only odd (whole) tracks are populated for a standard disk.
+
+	gcrHeaderBlockID = 0x08
+	gcrDataBlockID   = 0x07
+)
+
+var g64Magic = []byte("GCR-1541")
+
+// gcrDecodeTable maps a 5-bit GCR code to the nibble it represents; -1
+// means the code is not a valid GCR code (sync or corrupt data).
+var gcrDecodeTable = func() [32]int8 {
+	var t [32]int8
+	for i := range t {
+		t[i] = -1
+	}
+	nibbleToGCR := [16]byte{
+		0x0a, 0x0b, 0x12, 0x13, 0x0e, 0x0f, 0x16, 0x17,
+		0x09, 0x19, 0x1a, 0x1b, 0x0d, 0x1d, 0x1e, 0x15,
+	}
+	for nibble, code := range nibbleToGCR {
+		t[code] = int8(nibble)
+	}
+	return t
+}()
+
+// gcrBitReader reads an arbitrary bit stream out of a GCR byte slice.
+type gcrBitReader struct {
+	data []byte
+	pos  int // Bit position.
+}
+
+func (r *gcrBitReader) bit() int {
+	byteIdx := r.pos / 8
+	if byteIdx >= len(r.data) {
+		return -1
+	}
+	bitIdx := 7 - (r.pos % 8)
+	r.pos++
+	return int(r.data[byteIdx]>>bitIdx) & 1
+}
+
+// decodeGCRBytes decodes n raw bytes out of the GCR bitstream, 5 bits (one
+// GCR code) at a time, two nibbles per output byte.
+func (r *gcrBitReader) decodeGCRBytes(n int) ([]byte, error) {
+	out := make([]byte, n)
+	for i := 0; i < n; i++ {
+		var nibbles [2]byte
+		for h := 0; h < 2; h++ {
+			var code int
+			for b := 0; b < 5; b++ {
+				bit := r.bit()
+				if bit < 0 {
+					return nil, fmt.Errorf("GCR stream ended early")
+				}
+				code = (code << 1) | bit
+			}
+			nibble := gcrDecodeTable[code]
+			if nibble < 0 {
+				return nil, fmt.Errorf("invalid GCR code %05b", code)
+			}
+			nibbles[h] = byte(nibble)
+		}
+		out[i] = nibbles[0]<<4 | nibbles[1]
+	}
+	return out, nil
+}
+
+// findGCRSync scans forward from byte fromByte for a run of at least 5
+// consecutive 0xFF bytes (the sync mark preceding every GCR header/data
+// block) and returns the bit position immediately after it, or -1 if
+// none is found.
+func findGCRSync(data []byte, fromByte int) int {
+	run := 0
+	for i := fromByte; i < len(data); i++ {
+		if data[i] == 0xff {
+			run++
+			continue
+		}
+		if run >= 5 {
+			return i * 8
+		}
+		run = 0
+	}
+	return -1
+}
+
+// decodeGCRTrack decodes every sector found on one track's raw GCR data
+// into a map of sector number to its 256 data bytes.
+func decodeGCRTrack(trackData []byte) map[int][]byte {
+	sectors := make(map[int][]byte)
+
+	bytePos := 0
+	for {
+		syncBit := findGCRSync(trackData, bytePos)
+		if syncBit < 0 {
+			break
+		}
+
+		reader := &gcrBitReader{data: trackData, pos: syncBit}
+		header, err := reader.decodeGCRBytes(8)
+		if err != nil || header[0] != gcrHeaderBlockID {
+			bytePos = syncBit/8 + 1
+			continue
+		}
+		sector := int(header[2])
+
+		dataSyncBit := findGCRSync(trackData, reader.pos/8)
+		if dataSyncBit < 0 {
+			break
+		}
+		dataReader := &gcrBitReader{data: trackData, pos: dataSyncBit}
+		block, err := dataReader.decodeGCRBytes(260)
+		if err != nil || block[0] != gcrDataBlockID {
+			bytePos = dataSyncBit/8 + 1
+			continue
+		}
+
+		sectors[sector] = block[1:257]
+		bytePos = dataReader.pos/8 + 1
+	}
+
+	return sectors
+}
+
+// decodeG64ToD64 GCR-decodes a G64 image's whole (non-half) tracks and
+// rebuilds a standard 35-track D64 byte buffer so the existing D64
+// directory/extraction code can be reused unchanged.
+func decodeG64ToD64(g64Data []byte) ([]byte, error) {
+	if len(g64Data) < g64HeaderSize || !bytes.Equal(g64Data[:g64MagicSize], g64Magic) {
+		return nil, fmt.Errorf("not a G64 image: missing %q magic", g64Magic)
+	}
+
+	trackCount := int(g64Data[9])
+	offsetTableStart := g64HeaderSize
+	offsetTableEnd := offsetTableStart + trackCount*4
+	if offsetTableEnd > len(g64Data) {
+		return nil, fmt.Errorf("G64 track offset table truncated")
+	}
+
+	d64 := make([]byte, 174848) // Standard 35-track D64 size.
+
+	for halfTrack := 0; halfTrack < trackCount && halfTrack < g64TrackEntries; halfTrack++ {
+		track := halfTrack/2 + 1
+		if halfTrack%2 != 0 || track > d64Tracks35 {
+			continue // Only whole tracks map onto a D64 layout.
+		}
+
+		entryOffset := offsetTableStart + halfTrack*4
+		trackOffset := binary.LittleEndian.Uint32(g64Data[entryOffset : entryOffset+4])
+		if trackOffset == 0 || int(trackOffset)+2 > len(g64Data) {
+			continue
+		}
+
+		trackLen := binary.LittleEndian.Uint16(g64Data[trackOffset : trackOffset+2])
+		dataStart := int(trackOffset) + 2
+		dataEnd := dataStart + int(trackLen)
+		if dataEnd > len(g64Data) {
+			dataEnd = len(g64Data)
+		}
+
+		for sector, data := range decodeGCRTrack(g64Data[dataStart:dataEnd]) {
+			destOffset := getSectorOffset(track, sector)
+			if destOffset < 0 || destOffset+bytesPerSector > len(d64) {
+				continue
+			}
+			copy(d64[destOffset+2:destOffset+bytesPerSector], data[2:])
+			// The next-track/sector link is the decoded sector's own
+			// first two bytes, copied as-is, so directory and file
+			// chains follow the same links as on the original disk.
+			copy(d64[destOffset:destOffset+2], data[0:2])
+		}
+	}
+
+	return d64, nil
+}
+
+// extractFirstPRGFromG64 GCR-decodes a G64 image into D64 layout and
+// extracts its first PRG file.
+func extractFirstPRGFromG64(g64Data []byte) ([]byte, string, error) {
+	d64, err := decodeG64ToD64(g64Data)
+	if err != nil {
+		return nil, "", err
+	}
+	return extractFirstPRG(d64)
+}
@@ -0,0 +1,148 @@
+// Persistent search history and saved named queries. Recent free-text
+// searches and user-named advanced searches are kept under
+// $XDG_STATE_HOME/c64uploader (history.json and queries.json
+// respectively) so they survive restarts. Loading is lazy - see
+// Model.loadHistory/loadSavedQueries in tui.go - so startup latency is
+// unaffected when neither file exists yet.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxHistoryEntries caps how many recent search strings history.json
+// keeps; the oldest entries are dropped once the cap is reached.
+const maxHistoryEntries = 50
+
+// SavedQuery is a user-named advanced search, as bookmarked with Ctrl+S
+// and restored with Ctrl+O.
+type SavedQuery struct {
+	Name     string         `json:"name"`
+	Category string         `json:"category"`
+	Search   AdvancedSearch `json:"search"`
+}
+
+// HistoryStore persists recent search strings and saved named queries.
+// A small interface rather than bare functions so a test can inject an
+// in-memory fake instead of touching disk.
+type HistoryStore interface {
+	LoadHistory() ([]string, error)
+	SaveHistory(queries []string) error
+	LoadSavedQueries() ([]SavedQuery, error)
+	SaveSavedQueries(queries []SavedQuery) error
+}
+
+// fileHistoryStore is the default HistoryStore, backed by JSON files
+// under stateDir().
+type fileHistoryStore struct{}
+
+// LoadHistory reads history.json. A missing file is not an error - it
+// just means there's no history yet.
+func (fileHistoryStore) LoadHistory() ([]string, error) {
+	var queries []string
+	if err := loadStateFile("history.json", &queries); err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
+// SaveHistory writes queries to history.json, creating the state
+// directory if needed.
+func (fileHistoryStore) SaveHistory(queries []string) error {
+	return saveStateFile("history.json", queries)
+}
+
+// LoadSavedQueries reads queries.json. A missing file is not an error -
+// it just means there are no saved queries yet.
+func (fileHistoryStore) LoadSavedQueries() ([]SavedQuery, error) {
+	var saved []SavedQuery
+	if err := loadStateFile("queries.json", &saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// SaveSavedQueries writes queries to queries.json, creating the state
+// directory if needed.
+func (fileHistoryStore) SaveSavedQueries(queries []SavedQuery) error {
+	return saveStateFile("queries.json", queries)
+}
+
+// stateDir returns $XDG_STATE_HOME/c64uploader, falling back to
+// ~/.local/state/c64uploader per the XDG base directory spec (the Go
+// stdlib has os.UserConfigDir and os.UserCacheDir but no state-dir
+// equivalent).
+func stateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "c64uploader"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "c64uploader"), nil
+}
+
+// loadStateFile reads and JSON-decodes name from the state directory
+// into v. A missing file or state directory leaves v untouched rather
+// than returning an error.
+func loadStateFile(name string, v any) error {
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// saveStateFile JSON-encodes v and writes it to name in the state
+// directory, creating the directory on first use.
+func saveStateFile(name string, v any) error {
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// addHistoryEntry prepends query to history, dropping a prior duplicate
+// and trimming to maxHistoryEntries.
+func addHistoryEntry(history []string, query string) []string {
+	deduped := make([]string, 0, len(history)+1)
+	deduped = append(deduped, query)
+	for _, h := range history {
+		if h != query {
+			deduped = append(deduped, h)
+		}
+	}
+	if len(deduped) > maxHistoryEntries {
+		deduped = deduped[:maxHistoryEntries]
+	}
+	return deduped
+}
@@ -25,6 +25,20 @@ type ReleaseEntry struct {
 	CategoryName string // "Games", "Demos", "Music", etc.
 	FullPath     string // Absolute path to the file.
 	FileType     string // "d64", "prg", "crt" - from extension.
+
+	// Populated only for entries loaded from the JSON database (db.go);
+	// zero/nil for legacy .releaselog.json entries.
+	Top200Rank int
+	Crack      *CrackInfo
+	Language   string
+	Region     string
+	Engine     string
+	Is4k       bool
+
+	// Hash is the primary file's SHA-256 content hash, as recorded by
+	// dbgen (see DBFile.Hash in dbgen.go). Empty for entries loaded
+	// from legacy .releaselog.json files, which predate hashing.
+	Hash string
 }
 
 // SearchIndex holds all entries organized for fast searching.
@@ -194,7 +208,10 @@ func loadAssembly64Index(basePath string) (*SearchIndex, error) {
 
 // isSupportedExtension checks if a file extension is supported.
 func isSupportedExtension(ext string) bool {
-	supportedExts := []string{".d64", ".prg", ".crt", ".D64", ".PRG", ".CRT"}
+	supportedExts := []string{
+		".d64", ".prg", ".crt", ".t64", ".lnx",
+		".D64", ".PRG", ".CRT", ".T64", ".LNX",
+	}
 	for _, supported := range supportedExts {
 		if ext == supported {
 			return true
@@ -297,8 +314,8 @@ func findLoadableFile(dirPath string) (string, error) {
 		return "", fmt.Errorf("failed to read directory: %w", err)
 	}
 
-	// Priority order: .d64, .prg, .crt, other disk images.
-	exts := []string{".d64", ".prg", ".crt", ".d71", ".d81", ".g64", ".g71"}
+	// Priority order: .d64, .prg, .crt, other disk images, archive containers.
+	exts := []string{".d64", ".prg", ".crt", ".d71", ".d81", ".g64", ".g71", ".t64", ".lnx"}
 
 	for _, ext := range exts {
 		for _, entry := range entries {
@@ -0,0 +1,161 @@
+// LNX (Lynx) archive parsing and PRG extraction.
+//
+// A Lynx archive is a self-dissolving C64 PRG: a BASIC loader stub is
+// followed by a plain-text directory ("LYNX" marker, entry count, then one
+// comma-delimited "filename,blocks,type,lastbyte" line per file) and
+// finally the concatenated file data itself, stored block-aligned rather
+// than byte-exact. lastbyte gives the number of valid bytes in the file's
+// final 254-byte block, so extraction can drop the block's padding instead
+// of returning it as part of the file.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const lnxBlockSize = 254
+
+// lnxEntry is one directory entry in a Lynx archive.
+type lnxEntry struct {
+	filename   string
+	fileType   byte
+	sizeBlocks int
+	lastByte   int // Valid byte count in the final block; 0 means the block is full.
+	dataOffset int // Absolute offset of this entry's payload within the archive.
+}
+
+// findLynxDirectory locates the "LYNX" marker that starts a Lynx archive's
+// text directory and returns the offset just past it.
+func findLynxDirectory(data []byte) (int, error) {
+	idx := bytes.Index(data, []byte("LYNX"))
+	if idx < 0 {
+		return 0, fmt.Errorf("not a Lynx archive: no LYNX marker found")
+	}
+	return idx + len("LYNX"), nil
+}
+
+// parseLNXDirectory parses a Lynx archive's text directory and returns its
+// entries plus the offset where file data begins.
+func parseLNXDirectory(data []byte) ([]lnxEntry, int, error) {
+	offset, err := findLynxDirectory(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nextLine := func() (string, bool) {
+		end := bytes.IndexByte(data[offset:], 0x0d)
+		if end < 0 {
+			return "", false
+		}
+		line := string(data[offset : offset+end])
+		offset += end + 1
+		return line, true
+	}
+
+	countLine, ok := nextLine()
+	if !ok {
+		return nil, 0, fmt.Errorf("malformed Lynx directory: missing entry count")
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countLine))
+	if err != nil {
+		return nil, 0, fmt.Errorf("malformed Lynx directory: invalid entry count %q", countLine)
+	}
+
+	var entries []lnxEntry
+	for i := 0; i < count; i++ {
+		line, ok := nextLine()
+		if !ok {
+			break
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) < 4 {
+			continue
+		}
+
+		name := strings.TrimRight(strings.TrimSpace(fields[0]), " ")
+		sizeBlocks, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			continue
+		}
+		typeField := strings.TrimSpace(fields[2])
+		if typeField == "" {
+			continue
+		}
+		fileType := byte(strings.ToUpper(typeField)[0])
+		lastByte, err := strconv.Atoi(strings.TrimSpace(fields[3]))
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, lnxEntry{filename: name, fileType: fileType, sizeBlocks: sizeBlocks, lastByte: lastByte})
+	}
+
+	if len(entries) == 0 {
+		return nil, 0, fmt.Errorf("Lynx archive has no readable entries")
+	}
+
+	// Files are stored back-to-back in whole, block-aligned chunks
+	// starting at offset, so each entry's payload begins where the
+	// previous one's full (untrimmed) blocks end.
+	cursor := offset
+	for i := range entries {
+		entries[i].dataOffset = cursor
+		cursor += entries[i].sizeBlocks * lnxBlockSize
+	}
+
+	return entries, offset, nil
+}
+
+// lnxEntryLength returns the exact byte length of entry's payload: every
+// block but the last is full, and the last contributes only its lastByte
+// valid bytes (or the whole block, if lastByte wasn't given).
+func lnxEntryLength(entry lnxEntry) int {
+	if entry.sizeBlocks <= 0 {
+		return 0
+	}
+	if entry.lastByte <= 0 {
+		return entry.sizeBlocks * lnxBlockSize
+	}
+	return (entry.sizeBlocks-1)*lnxBlockSize + entry.lastByte
+}
+
+// extractLNXEntry extracts entry's payload from lnxData, trimmed to its
+// exact length using the directory's lastbyte field rather than the full
+// block-aligned size.
+func extractLNXEntry(lnxData []byte, entry lnxEntry) ([]byte, error) {
+	start := entry.dataOffset
+	if start < 0 || start > len(lnxData) {
+		return nil, fmt.Errorf("Lynx entry %q starts past end of file", entry.filename)
+	}
+	end := start + lnxEntryLength(entry)
+	if end > len(lnxData) {
+		end = len(lnxData)
+	}
+	return lnxData[start:end], nil
+}
+
+// extractFirstPRGFromLNX extracts the first PRG-type entry from a Lynx
+// archive.
+func extractFirstPRGFromLNX(lnxData []byte) ([]byte, string, error) {
+	entries, _, err := parseLNXDirectory(lnxData)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, entry := range entries {
+		if entry.fileType != 'P' {
+			continue
+		}
+		data, err := extractLNXEntry(lnxData, entry)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, entry.filename, nil
+	}
+
+	return nil, "", fmt.Errorf("no PRG entries found in Lynx archive")
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -11,9 +12,13 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tsaarni/c64uploader/uploader/filecache"
 )
 
 // isURL checks if a string is a URL.
@@ -66,67 +71,88 @@ func detectFileType(filename string) string {
 	}
 }
 
-// uploadAndRunFile uploads a file and runs it based on file type.
-func uploadAndRunFile(client *APIClient, fileData []byte, filename string) error {
+// uploadAndRunFile uploads a file and runs it based on file type,
+// reporting byte progress through progress if non-nil. fileData may be
+// nil, in which case it's fetched from filename first - a local path or
+// a URL, same as readFile - so callers that haven't already read the
+// file (e.g. an IndexProvider entry whose FullPath is a remote URL)
+// don't need to duplicate that dispatch themselves.
+func uploadAndRunFile(ctx context.Context, client *APIClient, fileData []byte, filename string, progress progressFunc) error {
 	// Detect file type.
 	fileType := detectFileType(filename)
 	if fileType == "" {
 		return fmt.Errorf("unsupported file type (supported: .prg, .crt, .d64, .d71, .d81, .g64, .g71)")
 	}
 
+	if fileData == nil {
+		data, err := readFile(filename)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", filename, err)
+		}
+		fileData = data
+	}
+
 	slog.Info("Detected file type", "type", fileType)
 	// Upload and run based on type.
 	switch fileType {
 	case "prg":
-		return client.runPRG(fileData)
+		return client.runPRG(ctx, fileData, progress)
 	case "crt":
-		return client.runCRT(fileData)
+		return client.runCRT(ctx, fileData, progress)
 	case "d64", "d71", "d81", "g64", "g71":
-		return client.runDiskImage(fileData, fileType, filepath.Base(filename))
+		return client.runDiskImage(ctx, fileData, fileType, filepath.Base(filename), true, progress)
 	default:
 		return fmt.Errorf("unsupported file type: %s", fileType)
 	}
 }
 
-// parsePoke parses a POKE address and value.
-func parsePoke(addressStr, valueStr string) (string, byte, error) {
-	// Parse address.
-	// C64 Ultimate API expects hex address without 0x or $.
-	// User might provide 53280, 0xD020, $D020.
-	var address int
-	var err error
+// addRetryFlags registers the MaxRetries/MinSleep/MaxSleep flags shared
+// by subcommands that talk to APIClient over Wi-Fi, returning a closure
+// that builds the resulting RetryPolicy once fs.Parse has run.
+func addRetryFlags(fs *flag.FlagSet) func() RetryPolicy {
+	maxRetries := fs.Int("max-retries", 0, "Max retries on transient network failures (0 disables retry)")
+	minSleep := fs.Duration("retry-min-sleep", 100*time.Millisecond, "Initial delay between retries")
+	maxSleep := fs.Duration("retry-max-sleep", 5*time.Second, "Maximum delay between retries")
+	return func() RetryPolicy {
+		return RetryPolicy{MaxRetries: *maxRetries, MinSleep: *minSleep, MaxSleep: *maxSleep}
+	}
+}
 
-	// Handle $ prefix for hex.
-	if strings.HasPrefix(addressStr, "$") {
-		addressStr = "0x" + addressStr[1:]
+// parseNumber parses a decimal, "0x"-prefixed, or "$"-prefixed hex
+// number (e.g. "53280", "0xD020", "$D020", or bare hex "D020").
+func parseNumber(s string) (int, error) {
+	if strings.HasPrefix(s, "$") {
+		s = "0x" + s[1:]
 	}
 
-	// Try parsing. auto-detect base (0x for hex, else decimal).
-	_, err = fmt.Sscanf(addressStr, "%v", &address)
+	var n int
+	// Try parsing, auto-detecting base (0x for hex, else decimal).
+	_, err := fmt.Sscanf(s, "%v", &n)
 	if err != nil {
-		// Re-attempt as hex if initial parse failed (e.g. "D020")
-		_, errHex := fmt.Sscanf(addressStr, "%x", &address)
+		// Re-attempt as hex if the initial parse failed (e.g. "D020").
+		_, errHex := fmt.Sscanf(s, "%x", &n)
 		if errHex != nil {
-			return "", 0, fmt.Errorf("invalid address '%s': %v", addressStr, err)
+			return 0, fmt.Errorf("invalid number '%s': %v", s, err)
 		}
 	}
+	return n, nil
+}
+
+// parsePoke parses a POKE address and value.
+func parsePoke(addressStr, valueStr string) (string, byte, error) {
+	// C64 Ultimate API expects hex address without 0x or $.
+	// User might provide 53280, 0xD020, $D020.
+	address, err := parseNumber(addressStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid address '%s': %w", addressStr, err)
+	}
 
 	// Format address as hex string for API (without 0x).
 	addressHex := fmt.Sprintf("%x", address)
 
-	// Parse value.
-	var value int
-	// Handle $ prefix
-	if strings.HasPrefix(valueStr, "$") {
-		valueStr = "0x" + valueStr[1:]
-	}
-	_, err = fmt.Sscanf(valueStr, "%v", &value)
+	value, err := parseNumber(valueStr)
 	if err != nil {
-		// Re-attempt as hex (e.g. "FF")
-		_, errHex := fmt.Sscanf(valueStr, "%x", &value)
-		if errHex != nil {
-			return "", 0, fmt.Errorf("invalid value '%s': %v", valueStr, err)
-		}
+		return "", 0, fmt.Errorf("invalid value '%s': %w", valueStr, err)
 	}
 
 	if value < 0 || value > 255 {
@@ -141,8 +167,10 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "Commands:\n")
 	fmt.Fprintf(os.Stderr, "  tui                       Launch the Terminal UI browser\n")
 	fmt.Fprintf(os.Stderr, "  load <filename>           Upload and run a file (PRG, CRT, D64, etc.)\n")
-	fmt.Fprintf(os.Stderr, "  ftp <filename> <dest>     Upload a file via FTP to C64 Ultimate\n")
+	fmt.Fprintf(os.Stderr, "  push <filename> <dest>    Upload a file to C64 Ultimate via FTP, SFTP, or WebDAV\n")
 	fmt.Fprintf(os.Stderr, "  poke <address>,<value>    Issue a POKE command to C64 memory\n")
+	fmt.Fprintf(os.Stderr, "  batch <manifest>          Upload a list of files/URLs in parallel, with resume\n")
+	fmt.Fprintf(os.Stderr, "  shell                     Open an interactive prompt for poke/peek/load/mount/type\n")
 	fmt.Fprintf(os.Stderr, "  server                    Start the C64 protocol server\n")
 	fmt.Fprintf(os.Stderr, "  dbgen                     Generate JSON database from Assembly64\n\n")
 	fmt.Fprintf(os.Stderr, "Run 'c64uploader <command> -help' for command-specific options.\n")
@@ -178,6 +206,9 @@ func runTUI(args []string) {
 	assembly64Path := fs.String("assembly64", "~/Downloads/assembly64", "Path to Assembly64 data directory")
 	dbPath := fs.String("db", "games.json", "Path to JSON database file")
 	legacy := fs.Bool("legacy", false, "Force legacy .releaselog.json loading")
+	themeName := fs.String("theme", "default", "Color theme to start with (see themes/ for user themes; cycle live with T)")
+	cacheSizeMB := fs.Int64("cache-size", 1024, "Max size in MB of ~/.cache/c64uploader, the local cache for downloaded catalog entries (0 disables eviction)")
+	retryPolicy := addRetryFlags(fs)
 	fs.Parse(args)
 
 	// Set log level.
@@ -188,7 +219,14 @@ func runTUI(args []string) {
 	// Disable slog output in TUI mode to avoid interfering with the display.
 	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
 
-	index, err := loadIndex(*assembly64Path, *dbPath, *legacy)
+	downloadCache, err := newDownloadCache(*cacheSizeMB * 1024 * 1024)
+	if err != nil {
+		// Caching is a convenience, not a requirement - fall back to
+		// fetching URL-backed entries straight into memory.
+		slog.Warn("Failed to open download cache, URL entries won't be cached", "error", err)
+	}
+
+	index, err := loadMergedIndex(*assembly64Path, *dbPath, *legacy)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to load index: %v\n", err)
 		os.Exit(1)
@@ -211,9 +249,10 @@ func runTUI(args []string) {
 
 	// Create API client.
 	client := NewAPIClient(*host)
+	client.RetryPolicy = retryPolicy()
 
 	// Launch TUI.
-	p := tea.NewProgram(NewModel(index, client, a64Path, legacyMode), tea.WithAltScreen())
+	p := tea.NewProgram(NewModel(index, client, a64Path, legacyMode, *themeName, *dbPath, downloadCache), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "TUI error: %v\n", err)
 		os.Exit(1)
@@ -224,6 +263,7 @@ func runLoad(args []string) {
 	fs := flag.NewFlagSet("load", flag.ExitOnError)
 	host := fs.String("host", "c64u", "C64 Ultimate hostname or IP address")
 	verbose := fs.Bool("v", false, "Enable verbose debug logging")
+	retryPolicy := addRetryFlags(fs)
 	fs.Parse(args)
 
 	// Set log level.
@@ -261,11 +301,12 @@ func runLoad(args []string) {
 
 	// Create API client.
 	client := NewAPIClient(*host)
+	client.RetryPolicy = retryPolicy()
 
 	slog.Info("Connecting to C64 Ultimate", "host", *host)
 	slog.Info("Uploading file", "path", input, "size", len(fileData))
 
-	if err := uploadAndRunFile(client, fileData, input); err != nil {
+	if err := uploadAndRunFile(context.Background(), client, fileData, input, nil); err != nil {
 		slog.Error("Failed to upload and run file", "error", err)
 		os.Exit(1)
 	}
@@ -316,7 +357,7 @@ func runPoke(args []string) {
 	// Create API client.
 	client := NewAPIClient(*host)
 
-	if err := client.WriteMemory(addressHex, []byte{value}); err != nil {
+	if err := client.WriteMemory(context.Background(), addressHex, []byte{value}); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to poke: %v\n", err)
 		os.Exit(1)
 	}
@@ -324,10 +365,17 @@ func runPoke(args []string) {
 	fmt.Printf("POKE %s,%d OK\n", addressStr, value)
 }
 
-func runFTP(args []string) {
-	fs := flag.NewFlagSet("ftp", flag.ExitOnError)
+// runPush uploads a single file to a destination reachable over FTP,
+// SFTP, or WebDAV - whichever -transport selects, or whatever the
+// destination's URL scheme implies (see detectTransportScheme).
+func runPush(args []string) {
+	fs := flag.NewFlagSet("push", flag.ExitOnError)
 	host := fs.String("host", "c64u", "C64 Ultimate hostname or IP address")
+	transportFlag := fs.String("transport", "", "Transport to use: ftp, sftp, or webdav (default: auto-detected from the destination's URL scheme, else ftp)")
+	insecureHostKey := fs.Bool("insecure-host-key", false, "Skip SFTP host-key verification instead of checking ~/.ssh/known_hosts")
+	checkExists := fs.Bool("exists", false, "Skip the upload if destination already exists on the remote")
 	verbose := fs.Bool("v", false, "Enable verbose debug logging")
+	retryPolicy := addRetryFlags(fs)
 	fs.Parse(args)
 
 	// Set log level.
@@ -337,15 +385,22 @@ func runFTP(args []string) {
 
 	if fs.NArg() < 2 {
 		fmt.Fprintf(os.Stderr, "Error: filename/URL and destination required\n")
-		fmt.Fprintf(os.Stderr, "Usage: c64uploader ftp [options] <filename|url> <destination>\n")
-		fmt.Fprintf(os.Stderr, "Example: c64uploader ftp ~/games/game.prg /Temp/game.prg\n")
-		fmt.Fprintf(os.Stderr, "Example: c64uploader ftp https://example.com/game.prg /Temp/game.prg\n")
+		fmt.Fprintf(os.Stderr, "Usage: c64uploader push [options] <filename|url> <destination>\n")
+		fmt.Fprintf(os.Stderr, "Example: c64uploader push ~/games/game.prg /Temp/game.prg\n")
+		fmt.Fprintf(os.Stderr, "Example: c64uploader push https://example.com/game.prg /Temp/game.prg\n")
+		fmt.Fprintf(os.Stderr, "Example: c64uploader push -transport sftp ~/games/game.prg /Temp/game.prg\n")
+		fmt.Fprintf(os.Stderr, "Example: c64uploader push ~/games/game.prg sftp://c64u/Temp/game.prg\n")
 		os.Exit(1)
 	}
 
 	input := fs.Arg(0)
 	destination := fs.Arg(1)
 
+	scheme, destPath := detectTransportScheme(destination)
+	if *transportFlag != "" {
+		scheme = transportScheme(*transportFlag)
+	}
+
 	// Load data from URL or local file.
 	var fileData []byte
 	var err error
@@ -366,27 +421,92 @@ func runFTP(args []string) {
 		}
 	}
 
-	// Create API client.
+	ctx := context.Background()
 	client := NewAPIClient(*host)
+	client.RetryPolicy = retryPolicy()
 
-	slog.Info("Connecting to C64 Ultimate FTP server", "host", *host)
-	slog.Info("Uploading file", "source", input, "destination", destination, "size", len(fileData))
+	slog.Info("Connecting to C64 Ultimate", "host", *host, "transport", scheme)
 
-	// Use the existing FTP upload method but with custom destination.
-	ftpAddr := fmt.Sprintf("%s:21", *host)
-	conn, err := client.ftpConnect(ftpAddr)
+	var remotePath string
+	err = client.withRetry(ctx, func() (bool, error) {
+		transport, err := newFileTransport(ctx, scheme, *host, *insecureHostKey)
+		if err != nil {
+			return isTransientError(err), err
+		}
+		defer transport.Close()
+
+		if *checkExists {
+			exists, err := transport.Exists(ctx, destPath)
+			if err != nil {
+				return isTransientError(err), err
+			}
+			if exists {
+				slog.Info("Destination already exists, skipping upload", "path", destPath)
+				remotePath = destPath
+				return false, nil
+			}
+		}
+
+		slog.Info("Uploading file", "source", input, "destination", destPath, "size", len(fileData))
+		if err := transport.Upload(ctx, fileData, destPath, nil); err != nil {
+			return isTransientError(err), err
+		}
+		remotePath = destPath
+		return false, nil
+	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to connect to FTP server: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to upload file: %v\n", err)
 		os.Exit(1)
 	}
-	defer conn.Quit()
 
-	if err := client.ftpUpload(conn, fileData, destination); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to upload file: %v\n", err)
+	fmt.Printf("File uploaded successfully to %s\n", remotePath)
+}
+
+func runBatch(args []string) {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	host := fs.String("host", "c64u", "Default C64 Ultimate hostname or IP address (used for manifest entries without their own host)")
+	verbose := fs.Bool("v", false, "Enable verbose debug logging")
+	workers := fs.Int("workers", 4, "Number of concurrent upload workers")
+	hostConcurrency := fs.Int("host-concurrency", 1, "Max concurrent uploads in flight against any single host")
+	resume := fs.Bool("resume", false, "Skip entries recorded as done in the manifest's sidecar state file, and keep it updated as uploads succeed")
+	retryPolicy := addRetryFlags(fs)
+	fs.Parse(args)
+
+	// Set log level.
+	if *verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	}
+
+	if fs.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Error: manifest required\n")
+		fmt.Fprintf(os.Stderr, "Usage: c64uploader batch [options] <manifest>\n")
+		fmt.Fprintf(os.Stderr, "Manifest is a plain text file (one file/URL per line, optionally \"source,host\"),\n")
+		fmt.Fprintf(os.Stderr, "a .json array of {\"source\":...,\"host\":...}, or a .csv with source,host columns.\n")
+		os.Exit(1)
+	}
+
+	manifestPath := fs.Arg(0)
+	entries, err := parseManifest(manifestPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: manifest is empty")
+		os.Exit(1)
+	}
+
+	state := batchState{Done: make(map[string]bool)}
+	if *resume {
+		state = loadBatchState(manifestPath)
+	}
 
-	fmt.Printf("File uploaded successfully to %s\n", destination)
+	failures := runBatchUpload(context.Background(), entries, *host, *workers, *hostConcurrency, retryPolicy(), state, *resume, manifestPath)
+	if failures > 0 {
+		fmt.Fprintf(os.Stderr, "Batch upload finished with %d failure(s)\n", failures)
+		os.Exit(1)
+	}
+	fmt.Println("Batch upload complete")
 }
 
 func runServer(args []string) {
@@ -397,6 +517,11 @@ func runServer(args []string) {
 	dbPath := fs.String("db", "games.json", "Path to JSON database file")
 	legacy := fs.Bool("legacy", false, "Force legacy .releaselog.json loading")
 	port := fs.Int("port", 6465, "C64 protocol server port")
+	nativePort := fs.Int("native-port", 0, "Native protocol server port, for legacy clients (0 to disable)")
+	jsonPort := fs.Int("json-port", 0, "JSON protocol server port, for debugging (0 to disable)")
+	ninepPort := fs.Int("9p-port", 0, "9P filesystem server port (0 to disable)")
+	webdavPort := fs.Int("webdav-port", 0, "WebDAV filesystem server port (0 to disable)")
+	cacheBudgetMB := fs.Int("cache-mb", 256, "Block cache memory budget in MB for entry file reads")
 	fs.Parse(args)
 
 	// Set log level.
@@ -404,7 +529,7 @@ func runServer(args []string) {
 		slog.SetLogLoggerLevel(slog.LevelDebug)
 	}
 
-	index, err := loadIndex(*assembly64Path, *dbPath, *legacy)
+	index, err := loadMergedIndex(*assembly64Path, *dbPath, *legacy)
 	if err != nil {
 		slog.Error("Failed to load index", "error", err)
 		os.Exit(1)
@@ -420,6 +545,47 @@ func runServer(args []string) {
 	// Create API client.
 	apiClient := NewAPIClient(*host)
 
+	// Install the shared block cache so repeated RUN requests for the
+	// same entry don't re-read and re-upload it from disk.
+	cache, err := filecache.NewCache(*cacheBudgetMB * 1024 * 1024)
+	if err != nil {
+		slog.Error("Failed to create file cache", "error", err)
+		os.Exit(1)
+	}
+	SetFileCache(cache)
+
+	// Start the 9P filesystem server, if requested.
+	if *ninepPort != 0 {
+		if err := StartNinePServer(*ninepPort, index, a64Path); err != nil {
+			slog.Error("9P server error", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Start the WebDAV filesystem server, if requested.
+	if *webdavPort != 0 {
+		if err := StartWebDAVServer(*webdavPort, index, a64Path); err != nil {
+			slog.Error("WebDAV server error", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Start the native protocol server, for legacy clients, if requested.
+	if *nativePort != 0 {
+		if err := StartNativeServer(*nativePort, index, apiClient, a64Path); err != nil {
+			slog.Error("Native server error", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Start the JSON protocol server, for debugging, if requested.
+	if *jsonPort != 0 {
+		if err := StartJSONServer(*jsonPort, index, apiClient, a64Path); err != nil {
+			slog.Error("JSON server error", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// Start C64 protocol server (blocking).
 	if err := StartC64Server(*port, index, apiClient, a64Path); err != nil {
 		slog.Error("C64 server error", "error", err)
@@ -434,11 +600,18 @@ func runDBGen(args []string) {
 	fs := flag.NewFlagSet("dbgen", flag.ExitOnError)
 	assembly64Path := fs.String("assembly64", "", "Path to Assembly64 data directory (required)")
 	outputPath := fs.String("output", "games.json", "Output JSON file path")
+	update := fs.Bool("update", false, "Incrementally update -output, skipping unchanged folders, and write a <output>.diff.json report")
+	verify := fs.Bool("verify", false, "Re-hash every entry in -output against the Assembly64 collection to detect bit-rot, without regenerating it")
+	shardByLetter := fs.Bool("shard-by-letter", false, "Write one JSON file per first-letter shard plus a <output-stem>-index.json manifest, instead of a single -output file")
+	include := fs.String("include", "", "Comma-separated glob patterns; only release folders matching at least one are scanned (default: all)")
+	exclude := fs.String("exclude", "", "Comma-separated glob patterns; release folders matching any are skipped")
+	merge := fs.String("merge", "", "Merge into this existing JSON database instead of -output, rescanning only release folders whose mtime/size fingerprint changed")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "Number of release folders to scan concurrently")
 	fs.Parse(args)
 
 	if *assembly64Path == "" {
 		fmt.Fprintf(os.Stderr, "Error: -assembly64 path is required\n")
-		fmt.Fprintf(os.Stderr, "Usage: c64uploader dbgen -assembly64 <path> [-output <file>]\n")
+		fmt.Fprintf(os.Stderr, "Usage: c64uploader dbgen -assembly64 <path> [-output <file>] [-update] [-merge <file>] [-verify] [-shard-by-letter] [-include <globs>] [-exclude <globs>] [-jobs N]\n")
 		fmt.Fprintf(os.Stderr, "Example: c64uploader dbgen -assembly64 /home/user/assembly64/Data -output games.json\n")
 		os.Exit(1)
 	}
@@ -460,12 +633,50 @@ func runDBGen(args []string) {
 		os.Exit(1)
 	}
 
-	if err := GenerateGamesDB(path, *outputPath); err != nil {
+	if *verify {
+		mismatches, err := VerifyGamesDB(path, *outputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(mismatches) == 0 {
+			fmt.Println("All entries verified OK")
+			return
+		}
+		fmt.Printf("Found %d mismatches:\n", len(mismatches))
+		for _, m := range mismatches {
+			fmt.Printf("  %s\n", m)
+		}
+		os.Exit(1)
+	}
+
+	cfg := ScanConfig{
+		Include: splitGlobList(*include),
+		Exclude: splitGlobList(*exclude),
+	}
+
+	if err := GenerateCatalogDBs(path, *outputPath, *update, *merge, *shardByLetter, cfg, *jobs); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// splitGlobList splits a comma-separated -include/-exclude flag value
+// into its individual glob patterns, ignoring empty entries so an unset
+// flag produces a nil slice rather than [""].
+func splitGlobList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var patterns []string
+	for _, p := range strings.Split(raw, ",") {
+		if p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -479,10 +690,14 @@ func main() {
 		runTUI(os.Args[2:])
 	case "load":
 		runLoad(os.Args[2:])
-	case "ftp":
-		runFTP(os.Args[2:])
+	case "push":
+		runPush(os.Args[2:])
 	case "poke":
 		runPoke(os.Args[2:])
+	case "batch":
+		runBatch(os.Args[2:])
+	case "shell":
+		runShell(os.Args[2:])
 	case "server":
 		runServer(os.Args[2:])
 	case "dbgen":
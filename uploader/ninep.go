@@ -0,0 +1,336 @@
+// 9P filesystem server exposing the Assembly64 catalog as a read-only tree.
+// Categories appear as top-level directories, entries as subdirectories
+// containing the release file plus a synthesized info.txt.
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hugelgupf/p9/linux"
+	"github.com/hugelgupf/p9/p9"
+)
+
+// StartNinePServer starts the 9P protocol server.
+// Clients (Ultimate II+ netdrive, u9fs, v9fs mounts) can browse and pull
+// files from the catalog without knowing our custom line protocol.
+func StartNinePServer(port int, index *SearchIndex, assembly64Path string) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to start 9P server: %w", err)
+	}
+
+	slog.Info("9P server listening", "port", port)
+	fmt.Printf("9P server listening on :%d\n", port)
+
+	root := &ninepRoot{index: index}
+	server := p9.NewServer(root)
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			slog.Error("9P server error", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// ninepRoot is the attach point for the 9P tree; it lists categories.
+type ninepRoot struct {
+	p9.DefaultWalkGetAttr
+	index *SearchIndex
+}
+
+// Attach returns the root directory of the catalog tree.
+func (r *ninepRoot) Attach() (p9.File, error) {
+	return &ninepDir{index: r.index}, nil
+}
+
+// ninepQID derives a stable QID from a node's tree path, so repeated Walks
+// of the same node return the same identity instead of a fresh one each
+// time the directory tree is traversed.
+func ninepQID(kind p9.QIDType, key string) p9.QID {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return p9.QID{Type: kind, Path: h.Sum64()}
+}
+
+// ninepReadOnly implements the mutating half of p9.File for a tree that
+// never has writers, device nodes, symlinks, or hard links: every
+// operation that would need one fails read-only (EROFS) or unsupported
+// (ENOSYS), and the handful of true no-ops (Close, FSync, Renamed) just
+// succeed. Embedded by ninepDir and ninepFile, which implement the
+// read-only surface (Walk/Readdir/ReadAt/Open/GetAttr) themselves.
+type ninepReadOnly struct {
+	p9.DefaultWalkGetAttr
+}
+
+func (ninepReadOnly) StatFS() (p9.FSStat, error) { return p9.FSStat{}, nil }
+
+func (ninepReadOnly) SetAttr(p9.SetAttrMask, p9.SetAttr) error { return linux.EROFS }
+
+func (ninepReadOnly) Close() error { return nil }
+
+func (ninepReadOnly) WriteAt([]byte, int64) (int, error) { return 0, linux.EROFS }
+
+func (ninepReadOnly) SetXattr(string, []byte, p9.XattrFlags) error { return linux.ENOSYS }
+
+func (ninepReadOnly) GetXattr(string) ([]byte, error) { return nil, linux.ENOSYS }
+
+func (ninepReadOnly) ListXattrs() ([]string, error) { return nil, linux.ENOSYS }
+
+func (ninepReadOnly) RemoveXattr(string) error { return linux.ENOSYS }
+
+func (ninepReadOnly) FSync() error { return nil }
+
+// Lock always succeeds: there are no writers to contend with on a
+// read-only tree, so a whole-file advisory lock is harmless to grant.
+func (ninepReadOnly) Lock(pid int, locktype p9.LockType, flags p9.LockFlags, start, length uint64, client string) (p9.LockStatus, error) {
+	return p9.LockStatusOK, nil
+}
+
+func (ninepReadOnly) Create(name string, flags p9.OpenFlags, permissions p9.FileMode, uid p9.UID, gid p9.GID) (p9.File, p9.QID, uint32, error) {
+	return nil, p9.QID{}, 0, linux.EROFS
+}
+
+func (ninepReadOnly) Mkdir(name string, permissions p9.FileMode, uid p9.UID, gid p9.GID) (p9.QID, error) {
+	return p9.QID{}, linux.EROFS
+}
+
+func (ninepReadOnly) Symlink(oldName, newName string, uid p9.UID, gid p9.GID) (p9.QID, error) {
+	return p9.QID{}, linux.EROFS
+}
+
+func (ninepReadOnly) Link(target p9.File, newName string) error { return linux.EROFS }
+
+func (ninepReadOnly) Mknod(name string, mode p9.FileMode, major, minor uint32, uid p9.UID, gid p9.GID) (p9.QID, error) {
+	return p9.QID{}, linux.EROFS
+}
+
+func (ninepReadOnly) Rename(newDir p9.File, newName string) error { return linux.EROFS }
+
+func (ninepReadOnly) RenameAt(oldName string, newDir p9.File, newName string) error {
+	return linux.EROFS
+}
+
+func (ninepReadOnly) UnlinkAt(name string, flags uint32) error { return linux.EROFS }
+
+func (ninepReadOnly) Readlink() (string, error) { return "", linux.EINVAL }
+
+func (ninepReadOnly) Renamed(newDir p9.File, newName string) {}
+
+// ninepDir represents either the root (categories) or a category
+// (entries) depending on whether category is set.
+type ninepDir struct {
+	ninepReadOnly
+	index    *SearchIndex
+	category string        // Empty at the root; set inside a category directory.
+	entry    *ReleaseEntry // Set when listing the contents of a single entry.
+}
+
+// qidKey identifies this directory's position in the tree for ninepQID.
+func (d *ninepDir) qidKey() string {
+	switch {
+	case d.entry != nil:
+		return "entry:" + d.category + "/" + d.entry.Name
+	case d.category != "":
+		return "category:" + d.category
+	default:
+		return "root"
+	}
+}
+
+// Walk resolves a path component against this directory's children.
+func (d *ninepDir) Walk(names []string) ([]p9.QID, p9.File, error) {
+	if len(names) == 0 {
+		return nil, d, nil
+	}
+
+	name := names[0]
+
+	switch {
+	case d.entry != nil:
+		// Inside an entry directory: the release file and info.txt.
+		if name == "info.txt" || name == d.entry.Name {
+			isInfo := name == "info.txt"
+			file := &ninepFile{entry: d.entry, isInfo: isInfo}
+			return []p9.QID{ninepQID(p9.TypeRegular, d.qidKey()+"/"+name)}, file, nil
+		}
+		return nil, nil, fmt.Errorf("no such file: %s", name)
+
+	case d.category == "":
+		// Root: resolve a category.
+		for _, cat := range d.index.CategoryOrder {
+			if cat == "All" {
+				continue
+			}
+			if strings.EqualFold(cat, name) {
+				dir := &ninepDir{index: d.index, category: cat}
+				return []p9.QID{ninepQID(p9.TypeDir, dir.qidKey())}, dir, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("no such category: %s", name)
+
+	default:
+		// Inside a category: resolve an entry by name.
+		for _, idx := range d.index.ByCategory[d.category] {
+			entry := d.index.Entries[idx]
+			if entry.Name == name {
+				dir := &ninepDir{index: d.index, category: d.category, entry: &entry}
+				return []p9.QID{ninepQID(p9.TypeDir, dir.qidKey())}, dir, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("no such entry: %s", name)
+	}
+}
+
+// Open grants read-only access to this directory's listing.
+func (d *ninepDir) Open(mode p9.OpenFlags) (p9.QID, uint32, error) {
+	if mode.Mode() != p9.ReadOnly {
+		return p9.QID{}, 0, linux.EROFS
+	}
+	return ninepQID(p9.TypeDir, d.qidKey()), 0, nil
+}
+
+// GetAttr reports this directory as read-only (mode 0555, no write bit).
+func (d *ninepDir) GetAttr(req p9.AttrMask) (p9.QID, p9.AttrMask, p9.Attr, error) {
+	qid := ninepQID(p9.TypeDir, d.qidKey())
+	attr := p9.Attr{Mode: p9.ModeDirectory | 0555}
+	return qid, p9.AttrMask{Mode: true}, attr, nil
+}
+
+// ReadAt fails: a directory has no byte contents to read.
+func (d *ninepDir) ReadAt(p []byte, offset int64) (int, error) {
+	return 0, linux.EISDIR
+}
+
+// Readdir lists the children of this directory.
+func (d *ninepDir) Readdir(offset uint64, count uint32) (p9.Dirents, error) {
+	var names []string
+
+	switch {
+	case d.entry != nil:
+		names = []string{"info.txt", d.entry.Name}
+	case d.category == "":
+		for _, cat := range d.index.CategoryOrder {
+			if cat != "All" {
+				names = append(names, cat)
+			}
+		}
+	default:
+		for _, idx := range d.index.ByCategory[d.category] {
+			names = append(names, d.index.Entries[idx].Name)
+		}
+	}
+
+	var dirents p9.Dirents
+	for i, name := range names {
+		if uint64(i) < offset {
+			continue
+		}
+		if uint32(len(dirents)) >= count {
+			break
+		}
+		dirents = append(dirents, p9.Dirent{Name: name, Offset: uint64(i) + 1})
+	}
+
+	return dirents, nil
+}
+
+// ninepFile serves either the release file bytes or the synthesized info.txt.
+type ninepFile struct {
+	ninepReadOnly
+	entry  *ReleaseEntry
+	isInfo bool
+}
+
+// qidKey identifies this file's position in the tree for ninepQID.
+func (f *ninepFile) qidKey() string {
+	if f.isInfo {
+		return "info:" + f.entry.Name
+	}
+	return "file:" + f.entry.Name
+}
+
+// Walk only allows resolving to itself; a regular file has no children.
+func (f *ninepFile) Walk(names []string) ([]p9.QID, p9.File, error) {
+	if len(names) == 0 {
+		return nil, f, nil
+	}
+	return nil, nil, linux.ENOTDIR
+}
+
+// Open grants read-only access to the file's contents.
+func (f *ninepFile) Open(mode p9.OpenFlags) (p9.QID, uint32, error) {
+	if mode.Mode() != p9.ReadOnly {
+		return p9.QID{}, 0, linux.EROFS
+	}
+	return ninepQID(p9.TypeRegular, f.qidKey()), 0, nil
+}
+
+// GetAttr reports this file as read-only (mode 0444, no write bit), with
+// Size backed by infoText's length for the synthesized info.txt or the
+// release file's on-disk size otherwise - without it, stat-based clients
+// (most 9P/FUSE mounts, cp, rsync) see every release file as zero bytes.
+func (f *ninepFile) GetAttr(req p9.AttrMask) (p9.QID, p9.AttrMask, p9.Attr, error) {
+	qid := ninepQID(p9.TypeRegular, f.qidKey())
+	attr := p9.Attr{Mode: p9.ModeRegular | 0444}
+	mask := p9.AttrMask{Mode: true}
+	if f.isInfo {
+		attr.Size = uint64(len(f.infoText()))
+		mask.Size = true
+	} else if info, err := os.Stat(f.entry.FullPath); err == nil {
+		attr.Size = uint64(info.Size())
+		mask.Size = true
+	}
+	return qid, mask, attr, nil
+}
+
+// ReadAt serves a read-only range of the file contents.
+func (f *ninepFile) ReadAt(p []byte, offset int64) (int, error) {
+	if f.isInfo {
+		text := f.infoText()
+		if offset >= int64(len(text)) {
+			return 0, nil
+		}
+		return copy(p, text[offset:]), nil
+	}
+	data, err := readFile(f.entry.FullPath)
+	if err != nil {
+		return 0, fmt.Errorf("reading entry file: %w", err)
+	}
+	if offset >= int64(len(data)) {
+		return 0, nil
+	}
+	return copy(p, data[offset:]), nil
+}
+
+// Readdir fails: a regular file cannot be listed as a directory.
+func (f *ninepFile) Readdir(offset uint64, count uint32) (p9.Dirents, error) {
+	return nil, linux.ENOTDIR
+}
+
+// infoText synthesizes handleInfo's NAME/GROUP/YEAR/CAT/TYPE fields, plus
+// TRAINER when crack info is known; it has no PATH line and no
+// TRAINER|unknown line.
+func (f *ninepFile) infoText() []byte {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("NAME|%s\n", f.entry.Name))
+	b.WriteString(fmt.Sprintf("GROUP|%s\n", f.entry.Group))
+	b.WriteString(fmt.Sprintf("YEAR|%s\n", f.entry.Year))
+	b.WriteString(fmt.Sprintf("CAT|%s\n", f.entry.CategoryName))
+	b.WriteString(fmt.Sprintf("TYPE|%s\n", f.entry.FileType))
+	if f.entry.Crack != nil {
+		b.WriteString(fmt.Sprintf("TRAINER|%d\n", f.entry.Crack.Trainers))
+	}
+	return []byte(b.String())
+}
+
+// ninepIdleTimeout is an idle limit for 9P connections. StartNinePServer
+// does not apply it, so no idle deadline is set on 9P connections.
+const ninepIdleTimeout = 5 * time.Minute
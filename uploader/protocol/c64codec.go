@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// C64Codec implements the original C64 dialect:
+//
+//	CATS                         - List categories
+//	LIST <cat> <offset> <count>  - List n entries from category starting at offset
+//	SEARCH <off> <n> <query>     - Search all entries (query can be multi-word)
+//	SEARCH <off> <n> <cat> <q>   - Search within category (cat=All for all)
+//	INFO <id>                    - Get entry details
+//	RUN <id>                     - Download and run entry
+//	ADVSEARCH <off> <n> [k=v...] - Search with field filters
+//	DOWNLOAD <id> [offset] [chunksize]
+//	RESUME <id> <offset> [chunksize]
+//	PART <id> <block>
+//	QUIT                         - Close connection
+type C64Codec struct{}
+
+// Name identifies the dialect for logging.
+func (C64Codec) Name() string { return "c64" }
+
+// Greeting is sent once when a client connects.
+func (C64Codec) Greeting() string { return "OK Assembly64 Browser\n" }
+
+// FormatError formats a dialect-appropriate error response.
+func (C64Codec) FormatError(msg string) string { return fmt.Sprintf("ERR %s\n", msg) }
+
+// FormatResponse passes the handler's response through unchanged.
+func (C64Codec) FormatResponse(raw string) string { return raw }
+
+// Goodbye is sent just before closing the connection after QUIT.
+func (C64Codec) Goodbye() string { return "OK Goodbye\n" }
+
+// Parse splits a request line into a command name and canonical arguments.
+// LIST/SEARCH canonical args are normalized to what the shared handlers
+// expect regardless of dialect; see NativeCodec for the native equivalent.
+func (C64Codec) Parse(line string) (string, []string, error) {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return "", nil, fmt.Errorf("empty command")
+	}
+
+	cmd := strings.ToUpper(parts[0])
+	rest := parts[1:]
+
+	switch cmd {
+	case "LIST":
+		// LIST <cat> <offset> <count>
+		if len(rest) < 3 {
+			return "", nil, fmt.Errorf("usage: LIST <category> <offset> <count>")
+		}
+		return cmd, []string{rest[0], rest[1], rest[2]}, nil
+
+	case "SEARCH":
+		// SEARCH <offset> <count> [category] <query...>
+		if len(rest) < 3 {
+			return "", nil, fmt.Errorf("usage: SEARCH <offset> <count> [category] <query>")
+		}
+		return cmd, []string{rest[0], rest[1], strings.Join(rest[2:], " ")}, nil
+
+	default:
+		return cmd, rest, nil
+	}
+}
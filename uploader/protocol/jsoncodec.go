@@ -0,0 +1,62 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonRequest is one newline-delimited JSON request: {"cmd":"LIST","args":["Games","0","20"]}.
+type jsonRequest struct {
+	Cmd  string   `json:"cmd"`
+	Args []string `json:"args"`
+}
+
+// jsonResponse wraps a handler's raw pipe-delimited response for easy
+// consumption with tools like `nc host port | jq`.
+type jsonResponse struct {
+	Raw   string `json:"raw,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// JSONCodec is a debugging-friendly dialect for host-side tooling: one JSON
+// object per line in, one JSON object per line out.
+type JSONCodec struct{}
+
+// Name identifies the dialect for logging.
+func (JSONCodec) Name() string { return "json" }
+
+// Greeting is sent once when a client connects.
+func (JSONCodec) Greeting() string {
+	b, _ := json.Marshal(jsonResponse{Raw: "Assembly64 Browser"})
+	return string(b) + "\n"
+}
+
+// FormatError formats a dialect-appropriate error response.
+func (JSONCodec) FormatError(msg string) string {
+	b, _ := json.Marshal(jsonResponse{Error: msg})
+	return string(b) + "\n"
+}
+
+// FormatResponse wraps a handler's raw response in a JSON envelope.
+func (JSONCodec) FormatResponse(raw string) string {
+	b, _ := json.Marshal(jsonResponse{Raw: raw})
+	return string(b) + "\n"
+}
+
+// Goodbye is sent just before closing the connection after QUIT.
+func (JSONCodec) Goodbye() string {
+	b, _ := json.Marshal(jsonResponse{Raw: "Goodbye"})
+	return string(b) + "\n"
+}
+
+// Parse decodes a JSON request line into its command name and arguments.
+func (JSONCodec) Parse(line string) (string, []string, error) {
+	var req jsonRequest
+	if err := json.Unmarshal([]byte(line), &req); err != nil {
+		return "", nil, fmt.Errorf("invalid JSON request: %w", err)
+	}
+	if req.Cmd == "" {
+		return "", nil, fmt.Errorf("missing cmd field")
+	}
+	return req.Cmd, req.Args, nil
+}
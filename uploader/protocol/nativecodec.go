@@ -0,0 +1,84 @@
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NativePageSize is the default page size used when LIST/SEARCH omit it.
+const NativePageSize = 20
+
+// NativeCodec implements the original native dialect, kept for backward
+// compatibility with existing clients:
+//
+//	CATS                    - List categories
+//	LIST <cat> <offset> <n> - List n entries from category starting at offset
+//	SEARCH <query> <off> <n>- Search entries
+//	INFO <id>               - Get entry details
+//	RUN <id>                - Download and run entry
+//	QUIT                    - Close connection
+//
+// Offset/count are optional here (unlike C64Codec) and default to 0/20;
+// search queries are always a single token.
+type NativeCodec struct{}
+
+// Name identifies the dialect for logging.
+func (NativeCodec) Name() string { return "native" }
+
+// Greeting is sent once when a client connects.
+func (NativeCodec) Greeting() string { return "OK Assembly64 Browser\n" }
+
+// FormatError formats a dialect-appropriate error response.
+func (NativeCodec) FormatError(msg string) string { return fmt.Sprintf("ERR %s\n", msg) }
+
+// FormatResponse passes the handler's response through unchanged.
+func (NativeCodec) FormatResponse(raw string) string { return raw }
+
+// Goodbye is sent just before closing the connection after QUIT.
+func (NativeCodec) Goodbye() string { return "OK Goodbye\n" }
+
+// Parse splits a request line into a command name and canonical arguments,
+// matching the shape C64Codec produces for the same shared handlers.
+func (NativeCodec) Parse(line string) (string, []string, error) {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return "", nil, fmt.Errorf("empty command")
+	}
+
+	cmd := strings.ToUpper(parts[0])
+	rest := parts[1:]
+
+	switch cmd {
+	case "LIST":
+		// LIST <cat> [offset] [count]
+		if len(rest) < 1 {
+			return "", nil, fmt.Errorf("usage: LIST <category> [offset] [count]")
+		}
+		offset, count := "0", strconv.Itoa(NativePageSize)
+		if len(rest) >= 2 {
+			offset = rest[1]
+		}
+		if len(rest) >= 3 {
+			count = rest[2]
+		}
+		return cmd, []string{rest[0], offset, count}, nil
+
+	case "SEARCH":
+		// SEARCH <query> [offset] [count] - query is always a single token.
+		if len(rest) < 1 {
+			return "", nil, fmt.Errorf("usage: SEARCH <query> [offset] [count]")
+		}
+		offset, count := "0", strconv.Itoa(NativePageSize)
+		if len(rest) >= 2 {
+			offset = rest[1]
+		}
+		if len(rest) >= 3 {
+			count = rest[2]
+		}
+		return cmd, []string{offset, count, rest[0]}, nil
+
+	default:
+		return cmd, rest, nil
+	}
+}
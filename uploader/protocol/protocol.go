@@ -0,0 +1,133 @@
+// Package protocol owns the connection loop, timeouts, and command dispatch
+// shared by the various line-based Assembly64 browser dialects (C64, native,
+// JSON). Each dialect only supplies a Codec that parses a request line into
+// a command name and arguments and serializes a response; the command set
+// itself lives in a single Registry so new commands don't require touching
+// every dialect.
+package protocol
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+)
+
+// ReadTimeout bounds how long a connection may sit idle between commands.
+const ReadTimeout = 5 * time.Minute
+
+// Handler processes one command's arguments and returns the response to
+// write back to the client. A handler that streams its own response
+// directly to conn (e.g. DOWNLOAD) returns an empty string.
+type Handler func(args []string, conn net.Conn) string
+
+// Registry maps upper-cased command names to their handler. The same
+// registry is shared across every Codec.
+type Registry map[string]Handler
+
+// Codec parses a dialect's request line into a command name/args pair and
+// formats protocol-level responses (errors, goodbye) for that dialect.
+type Codec interface {
+	// Name identifies the dialect for logging.
+	Name() string
+	// Parse splits a trimmed request line into a command name and its
+	// arguments, in whatever order this dialect expects them.
+	Parse(line string) (cmd string, args []string, err error)
+	// Greeting is sent once when a client connects.
+	Greeting() string
+	// FormatError formats a dialect-appropriate error response.
+	FormatError(msg string) string
+	// FormatResponse wraps a handler's raw response for the wire, if the
+	// dialect needs to (e.g. JSONCodec wraps it in a JSON envelope).
+	FormatResponse(raw string) string
+	// Goodbye is sent just before closing the connection after QUIT.
+	Goodbye() string
+}
+
+// Server owns a listener and dispatches parsed commands from any number of
+// concurrent connections to the shared registry.
+type Server struct {
+	codec    Codec
+	registry Registry
+}
+
+// NewServer creates a server for a single dialect backed by registry.
+func NewServer(codec Codec, registry Registry) *Server {
+	return &Server{codec: codec, registry: registry}
+}
+
+// Start starts listening on port and serves connections in the background.
+func (s *Server) Start(port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to start %s server: %w", s.codec.Name(), err)
+	}
+
+	slog.Info("Protocol server listening", "dialect", s.codec.Name(), "port", port)
+	fmt.Printf("%s protocol server listening on :%d\n", s.codec.Name(), port)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				slog.Error("Accept error", "dialect", s.codec.Name(), "error", err)
+				continue
+			}
+			go s.handleConnection(conn)
+		}
+	}()
+
+	return nil
+}
+
+func (s *Server) handleConnection(conn net.Conn) {
+	defer conn.Close()
+
+	remoteAddr := conn.RemoteAddr().String()
+	slog.Info("Client connected", "dialect", s.codec.Name(), "remote", remoteAddr)
+
+	conn.Write([]byte(s.codec.Greeting()))
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(ReadTimeout))
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			slog.Debug("Client disconnected", "dialect", s.codec.Name(), "remote", remoteAddr, "error", err)
+			return
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		slog.Debug("Command", "dialect", s.codec.Name(), "remote", remoteAddr, "line", line)
+
+		cmd, args, err := s.codec.Parse(line)
+		if err != nil {
+			conn.Write([]byte(s.codec.FormatError(err.Error())))
+			continue
+		}
+
+		if cmd == "QUIT" {
+			conn.Write([]byte(s.codec.Goodbye()))
+			return
+		}
+
+		handler, ok := s.registry[cmd]
+		if !ok {
+			conn.Write([]byte(s.codec.FormatError(fmt.Sprintf("Unknown command: %s", cmd))))
+			continue
+		}
+
+		response := handler(args, conn)
+		if response != "" {
+			conn.Write([]byte(s.codec.FormatResponse(response)))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// CSDb IndexProvider: pulls release metadata directly from a CSDb-style
+// JSON search endpoint rather than a local Assembly64 mirror, so recent
+// releases are available before anyone's had a chance to sync them into
+// the Games/CSDB/... tree that loadAssembly64Index scans.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// csdbProvider fetches releases from a CSDb JSON search endpoint.
+// BaseURL defaults to the public instance but can be pointed at a mirror
+// or test server via providers.yaml's "url" option.
+type csdbProvider struct {
+	BaseURL  string
+	Category string
+}
+
+// csdbSearchResponse is the shape of BaseURL's search results.
+type csdbSearchResponse struct {
+	Releases []csdbRelease `json:"releases"`
+}
+
+type csdbRelease struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	GroupName   string `json:"group"`
+	ReleaseDate string `json:"release_date"`
+	DownloadURL string `json:"download_url"`
+}
+
+// newCSDbProvider builds a csdbProvider from providers.yaml options.
+// "url" is required; "category" defaults to "Games" since that's what
+// most CSDb releases are.
+func newCSDbProvider(options map[string]string) (IndexProvider, error) {
+	url := options["url"]
+	if url == "" {
+		return nil, fmt.Errorf("csdb provider requires an \"url\" option")
+	}
+	category := options["category"]
+	if category == "" {
+		category = "Games"
+	}
+	return &csdbProvider{BaseURL: url, Category: category}, nil
+}
+
+func (p *csdbProvider) Name() string { return "csdb" }
+
+func (p *csdbProvider) Load(ctx context.Context) ([]ReleaseEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.BaseURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", p.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, p.BaseURL)
+	}
+
+	var body csdbSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	entries := make([]ReleaseEntry, 0, len(body.Releases))
+	for _, r := range body.Releases {
+		if r.DownloadURL == "" {
+			continue
+		}
+		entries = append(entries, ReleaseEntry{
+			Name:         r.Name,
+			Group:        r.GroupName,
+			Year:         r.ReleaseDate,
+			ID:           fmt.Sprintf("csdb-%d", r.ID),
+			CategoryName: p.Category,
+			FullPath:     r.DownloadURL,
+			FileType:     fileTypeFromPath(r.DownloadURL),
+		})
+	}
+
+	return entries, nil
+}
+
+// fileTypeFromPath extracts the lowercase extension (without the dot)
+// from a URL path, the way FullPath resolution does for local files.
+func fileTypeFromPath(rawURL string) string {
+	ext := path.Ext(rawURL)
+	if len(ext) < 2 {
+		return ""
+	}
+	return strings.ToLower(ext[1:])
+}
@@ -0,0 +1,85 @@
+// GameBase64 IndexProvider: imports releases from a GameBase64-style
+// "Games.sdb" SQLite export, the frontend most GameBase64 users already
+// have sitting on disk, instead of requiring its contents to first be
+// unpacked into an Assembly64-shaped directory tree.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	_ "modernc.org/sqlite"
+)
+
+// gameBase64Provider imports entries from a GameBase64 SQLite database.
+// GamesDir is where the database's relative Filename/Path columns
+// resolve to - the GameBase64 install's "Games" directory.
+type gameBase64Provider struct {
+	DBPath   string
+	GamesDir string
+}
+
+// newGameBase64Provider builds a gameBase64Provider from providers.yaml
+// options. Both "db" (path to Games.sdb) and "path" (the Games
+// directory the database's paths are relative to) are required.
+func newGameBase64Provider(options map[string]string) (IndexProvider, error) {
+	dbPath := options["db"]
+	gamesDir := options["path"]
+	if dbPath == "" || gamesDir == "" {
+		return nil, fmt.Errorf(`gamebase64 provider requires "db" and "path" options`)
+	}
+	return &gameBase64Provider{DBPath: dbPath, GamesDir: gamesDir}, nil
+}
+
+func (p *gameBase64Provider) Name() string { return "gamebase64" }
+
+// Load queries GameBase64's Games table. The schema below matches the
+// columns GameBase64 itself relies on for launching a game: GA_Id,
+// GA_Name, GA_FileToRun (path to the loadable file, relative to
+// GamesDir), GA_Year and GA_V_Publisher from GameBase64's lookup views.
+func (p *gameBase64Provider) Load(ctx context.Context) ([]ReleaseEntry, error) {
+	db, err := sql.Open("sqlite", p.DBPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", p.DBPath, err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT GA_Id, GA_Name, GA_FileToRun, GA_Year, GA_V_Publisher
+		FROM Games
+		WHERE GA_FileToRun IS NOT NULL AND GA_FileToRun != ''
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying Games table: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []ReleaseEntry
+	for rows.Next() {
+		var id int
+		var name, fileToRun, year, publisher string
+		if err := rows.Scan(&id, &name, &fileToRun, &year, &publisher); err != nil {
+			return nil, fmt.Errorf("scanning Games row: %w", err)
+		}
+
+		fullPath := fileToRun
+		if !filepath.IsAbs(fullPath) {
+			fullPath = filepath.Join(p.GamesDir, fileToRun)
+		}
+
+		entries = append(entries, ReleaseEntry{
+			Name:         name,
+			Group:        publisher,
+			Year:         year,
+			ID:           "gamebase64-" + strconv.Itoa(id),
+			CategoryName: "Games",
+			FullPath:     fullPath,
+			FileType:     fileTypeFromPath(fullPath),
+		})
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,115 @@
+// HTTP directory listing IndexProvider: treats a plain Apache/nginx
+// "Index of /..." page as a catalog, for sites that don't expose
+// anything richer than autoindex - one ReleaseEntry per linked file with
+// a supported extension, discovered the same way isSupportedExtension
+// gates local directory scans in index.go.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// httpDirProvider lists a single HTTP directory index page and turns
+// each loadable link into a ReleaseEntry.
+type httpDirProvider struct {
+	URL      string
+	Category string
+}
+
+// newHTTPDirProvider builds an httpDirProvider from providers.yaml
+// options. "url" is required; "category" defaults to "Misc".
+func newHTTPDirProvider(options map[string]string) (IndexProvider, error) {
+	dirURL := options["url"]
+	if dirURL == "" {
+		return nil, fmt.Errorf("httpdir provider requires an \"url\" option")
+	}
+	category := options["category"]
+	if category == "" {
+		category = "Misc"
+	}
+	return &httpDirProvider{URL: dirURL, Category: category}, nil
+}
+
+func (p *httpDirProvider) Name() string { return "httpdir:" + p.URL }
+
+func (p *httpDirProvider) Load(ctx context.Context) ([]ReleaseEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", p.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, p.URL)
+	}
+
+	links, err := extractLinks(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing directory listing: %w", err)
+	}
+
+	base, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base URL: %w", err)
+	}
+
+	var entries []ReleaseEntry
+	for _, link := range links {
+		fileType := fileTypeFromPath(link)
+		if !isSupportedExtension("." + fileType) {
+			continue
+		}
+
+		target, err := base.Parse(link)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, ReleaseEntry{
+			Name:         strings.TrimSuffix(path.Base(target.Path), path.Ext(target.Path)),
+			CategoryName: p.Category,
+			FullPath:     target.String(),
+			FileType:     fileType,
+		})
+	}
+
+	return entries, nil
+}
+
+// extractLinks returns every href among an HTML document's <a> tags.
+func extractLinks(body io.Reader) ([]string, error) {
+	doc, err := html.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			for _, attr := range n.Attr {
+				if attr.Key == "href" {
+					links = append(links, attr.Val)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return links, nil
+}
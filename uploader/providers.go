@@ -0,0 +1,184 @@
+// IndexProvider lets the TUI and protocol servers merge ReleaseEntry
+// results from more than one source - the on-disk Assembly64 tree plus
+// any of the remote catalogs in provider_*.go - the way rclone's Fs
+// interface lets a single "remote" be backed by interchangeable storage
+// backends. A provider's entries carry a FullPath that may be either a
+// local path or a URL; readFile and uploadAndRunFile fetch URLs
+// transparently, so callers never need to know which provider an entry
+// came from.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IndexProvider contributes a set of ReleaseEntry values to a merged
+// SearchIndex. Name identifies it in logs and in providers.yaml.
+type IndexProvider interface {
+	Name() string
+	Load(ctx context.Context) ([]ReleaseEntry, error)
+}
+
+// assembly64Provider adapts the existing loadIndex (legacy
+// .releaselog.json scan or JSON database, see index.go/db.go) to
+// IndexProvider, so the on-disk Assembly64 collection is always just
+// another provider in the merged index.
+type assembly64Provider struct {
+	assembly64Path string
+	dbPath         string
+	forceLegacy    bool
+}
+
+func (p *assembly64Provider) Name() string { return "assembly64" }
+
+func (p *assembly64Provider) Load(ctx context.Context) ([]ReleaseEntry, error) {
+	index, err := loadIndex(p.assembly64Path, p.dbPath, p.forceLegacy)
+	if err != nil {
+		return nil, err
+	}
+	return index.Entries, nil
+}
+
+// mergeProviders loads every provider and folds their entries into a
+// single SearchIndex, building ByCategory/CategoryOrder exactly as a
+// single-source load would: "All" first, then categories in the order
+// they're first seen. A provider that fails to load is logged and
+// skipped rather than failing the whole merge, since the point of
+// having several providers is that one being unreachable (a catalog
+// site down, a missing GameBase64 export) shouldn't take the rest with
+// it.
+func mergeProviders(ctx context.Context, providers []IndexProvider) *SearchIndex {
+	index := &SearchIndex{
+		ByCategory:    make(map[string][]int),
+		CategoryOrder: []string{"All"},
+	}
+	seenCategory := make(map[string]bool)
+
+	for _, p := range providers {
+		entries, err := p.Load(ctx)
+		if err != nil {
+			slog.Warn("Provider failed to load, skipping", "provider", p.Name(), "error", err)
+			continue
+		}
+
+		for _, entry := range entries {
+			idx := len(index.Entries)
+			index.Entries = append(index.Entries, entry)
+			index.ByCategory["All"] = append(index.ByCategory["All"], idx)
+
+			if entry.CategoryName == "" {
+				continue
+			}
+			index.ByCategory[entry.CategoryName] = append(index.ByCategory[entry.CategoryName], idx)
+			if !seenCategory[entry.CategoryName] {
+				seenCategory[entry.CategoryName] = true
+				index.CategoryOrder = append(index.CategoryOrder, entry.CategoryName)
+			}
+		}
+
+		slog.Info("Provider loaded", "provider", p.Name(), "entries", len(entries))
+	}
+
+	return index
+}
+
+// providerConfig is one entry of providers.yaml's top-level list.
+type providerConfig struct {
+	Type    string            `yaml:"type"`
+	Enabled bool              `yaml:"enabled"`
+	Options map[string]string `yaml:"options"`
+}
+
+// providersConfigPath returns $XDG_CONFIG_HOME/c64uploader/providers.yaml
+// (or the platform's equivalent user config directory).
+func providersConfigPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "c64uploader", "providers.yaml"), nil
+}
+
+// loadProviderConfigs reads providers.yaml. A missing file is not an
+// error - it just means no extra providers are configured.
+func loadProviderConfigs() ([]providerConfig, error) {
+	path, err := providersConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var configs []providerConfig
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return configs, nil
+}
+
+// buildConfiguredProviders turns each enabled entry of providers.yaml
+// into an IndexProvider. An unknown type or one that fails to build is
+// logged and skipped, the same way a single failed Load is - a typo in
+// one provider's config shouldn't stop the others from loading.
+func buildConfiguredProviders(configs []providerConfig) []IndexProvider {
+	var providers []IndexProvider
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+
+		provider, err := newProvider(cfg.Type, cfg.Options)
+		if err != nil {
+			slog.Warn("Skipping provider from providers.yaml", "type", cfg.Type, "error", err)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// newProvider builds the IndexProvider named by providerType, configured
+// with options from its providers.yaml entry.
+func newProvider(providerType string, options map[string]string) (IndexProvider, error) {
+	switch providerType {
+	case "csdb":
+		return newCSDbProvider(options)
+	case "gamebase64":
+		return newGameBase64Provider(options)
+	case "httpdir":
+		return newHTTPDirProvider(options)
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", providerType)
+	}
+}
+
+// loadMergedIndex builds the index the TUI and servers browse: the
+// always-present Assembly64 provider (driven by the -assembly64/-db/
+// -legacy flags, unchanged from before providers existed) plus whatever
+// extra providers are enabled in providers.yaml.
+func loadMergedIndex(assembly64Path, dbPath string, forceLegacy bool) (*SearchIndex, error) {
+	providers := []IndexProvider{
+		&assembly64Provider{assembly64Path: assembly64Path, dbPath: dbPath, forceLegacy: forceLegacy},
+	}
+
+	configs, err := loadProviderConfigs()
+	if err != nil {
+		slog.Warn("Failed to load providers.yaml, using Assembly64 only", "error", err)
+	} else {
+		providers = append(providers, buildConfiguredProviders(configs)...)
+	}
+
+	return mergeProviders(context.Background(), providers), nil
+}
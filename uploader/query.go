@@ -0,0 +1,332 @@
+// Compact search DSL for the normal search bar. Instead of opening the
+// advanced search form, power users can type terms like
+// `group:"Fairlight" trainers:>5 -type:crt cat:Games` directly into the
+// search box; applyFilters hands anything structured off to
+// applyAdvancedFilters. Inspired by IMAP's SearchCriteria and the
+// `tag:value` filter prompts found in other TUI explorers.
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsedQuery is the result of parsing the compact search DSL. It carries
+// the same criteria as the advanced search form (AdvancedSearch) plus
+// negated field terms, a category override, and leftover free-text
+// tokens that fall back to Name/Group substring matching.
+type ParsedQuery struct {
+	Adv        AdvancedSearch
+	Category   string // From cat:value, empty if not specified.
+	Excludes   []QueryExclude
+	FreeTerms  []string // Bare tokens (no recognized field), "-" prefix negates.
+	Structured bool     // True once any field:value predicate was parsed.
+}
+
+// QueryExclude is a negated field:value term, e.g. `-group:"some group"`.
+// The trainers field uses Min/Max like AdvancedSearch's own range; every
+// other field is a case-insensitive substring/equality match on Value.
+type QueryExclude struct {
+	Field          string
+	Value          string
+	Min, Max       int
+	HasMin, HasMax bool
+}
+
+// queryFields are the recognized "field:" prefixes, mirroring the labels
+// on the advanced search form plus "cat" for selectedCategory.
+var queryFields = map[string]bool{
+	"title": true, "group": true, "lang": true, "region": true,
+	"engine": true, "type": true, "trainers": true, "top200": true,
+	"4k": true, "docs": true, "fastload": true, "crack": true, "cat": true,
+}
+
+// parseQuery parses the compact search DSL: whitespace-separated terms of
+// the form field:value, field:"quoted value", field:>N, field:<=N, or
+// field:N..M, each optionally negated with a leading '-'. Tokens with no
+// recognized field are treated as free text.
+func parseQuery(input string) (ParsedQuery, error) {
+	var q ParsedQuery
+	q.Adv.MaxTrainers = -1
+
+	for _, tok := range tokenizeQuery(input) {
+		if tok == "" {
+			continue
+		}
+
+		negate := false
+		if strings.HasPrefix(tok, "-") && len(tok) > 1 {
+			negate = true
+			tok = tok[1:]
+		}
+
+		field, value, ok := splitFieldValue(tok)
+		if !ok || !queryFields[strings.ToLower(field)] {
+			term := tok
+			if negate {
+				term = "-" + term
+			}
+			q.FreeTerms = append(q.FreeTerms, term)
+			continue
+		}
+
+		if err := q.applyField(strings.ToLower(field), value, negate); err != nil {
+			return ParsedQuery{}, err
+		}
+		q.Structured = true
+	}
+
+	return q, nil
+}
+
+// tokenizeQuery splits input on whitespace, keeping double-quoted
+// segments (which may contain spaces) intact as a single token.
+func tokenizeQuery(input string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case r == ' ' && !inQuotes:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// splitFieldValue splits "field:value" on the first colon and strips
+// surrounding quotes from the value. ok is false if there is no colon.
+func splitFieldValue(tok string) (field, value string, ok bool) {
+	idx := strings.Index(tok, ":")
+	if idx <= 0 {
+		return "", "", false
+	}
+	field = tok[:idx]
+	value = tok[idx+1:]
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	return field, value, true
+}
+
+// applyField folds one parsed field:value term into q, either as a
+// positive AdvancedSearch predicate or, when negated, as a QueryExclude.
+func (q *ParsedQuery) applyField(field, value string, negate bool) error {
+	switch field {
+	case "title", "group", "lang", "region", "engine", "type":
+		if negate {
+			q.Excludes = append(q.Excludes, QueryExclude{Field: field, Value: value})
+			return nil
+		}
+		switch field {
+		case "title":
+			q.Adv.Title = value
+		case "group":
+			q.Adv.Group = value
+		case "lang":
+			q.Adv.Language = value
+		case "region":
+			q.Adv.Region = value
+		case "engine":
+			q.Adv.Engine = value
+		case "type":
+			q.Adv.FileType = value
+		}
+		return nil
+
+	case "cat":
+		q.Category = value
+		return nil
+
+	case "trainers":
+		min, max, hasMin, hasMax, err := parseNumericRange(value)
+		if err != nil {
+			return fmt.Errorf("trainers: %w", err)
+		}
+		if negate {
+			q.Excludes = append(q.Excludes, QueryExclude{Field: field, Min: min, Max: max, HasMin: hasMin, HasMax: hasMax})
+			return nil
+		}
+		if hasMin {
+			q.Adv.MinTrainers = min
+		}
+		if hasMax {
+			q.Adv.MaxTrainers = max
+		}
+		return nil
+
+	case "top200", "4k", "docs", "fastload":
+		on, err := parseQueryBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if negate {
+			on = !on
+		}
+		switch field {
+		case "top200":
+			q.Adv.Top200Only = on
+		case "4k":
+			q.Adv.Is4kOnly = on
+		case "docs":
+			q.Adv.HasDocs = on
+		case "fastload":
+			q.Adv.HasFastload = on
+		}
+		return nil
+
+	case "crack":
+		on, err := parseQueryBool(value)
+		if err != nil {
+			return fmt.Errorf("crack: %w", err)
+		}
+		if negate {
+			on = !on
+		}
+		q.Adv.IsCracked = &on
+		return nil
+	}
+
+	return fmt.Errorf("unknown field %q", field)
+}
+
+// parseNumericRange parses a trainers: value in any of the forms
+// ">N", ">=N", "<N", "<=N", "N..M", or a bare "N" (treated as exactly N).
+func parseNumericRange(value string) (min, max int, hasMin, hasMax bool, err error) {
+	switch {
+	case strings.HasPrefix(value, ">="):
+		n, perr := strconv.Atoi(value[2:])
+		if perr != nil {
+			return 0, 0, false, false, fmt.Errorf("invalid number %q", value[2:])
+		}
+		return n, 0, true, false, nil
+
+	case strings.HasPrefix(value, "<="):
+		n, perr := strconv.Atoi(value[2:])
+		if perr != nil {
+			return 0, 0, false, false, fmt.Errorf("invalid number %q", value[2:])
+		}
+		return 0, n, false, true, nil
+
+	case strings.HasPrefix(value, ">"):
+		n, perr := strconv.Atoi(value[1:])
+		if perr != nil {
+			return 0, 0, false, false, fmt.Errorf("invalid number %q", value[1:])
+		}
+		return n + 1, 0, true, false, nil
+
+	case strings.HasPrefix(value, "<"):
+		n, perr := strconv.Atoi(value[1:])
+		if perr != nil {
+			return 0, 0, false, false, fmt.Errorf("invalid number %q", value[1:])
+		}
+		return 0, n - 1, false, true, nil
+
+	case strings.Contains(value, ".."):
+		parts := strings.SplitN(value, "..", 2)
+		lo, err1 := strconv.Atoi(parts[0])
+		hi, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil {
+			return 0, 0, false, false, fmt.Errorf("invalid range %q", value)
+		}
+		return lo, hi, true, true, nil
+
+	default:
+		n, perr := strconv.Atoi(value)
+		if perr != nil {
+			return 0, 0, false, false, fmt.Errorf("invalid number %q", value)
+		}
+		return n, n, true, true, nil
+	}
+}
+
+// parseQueryBool parses the tri-state-friendly boolean forms accepted by
+// top200:, 4k:, docs:, fastload:, and crack:. An empty value (bare
+// "field:") is treated as true.
+func parseQueryBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1", "on", "":
+		return true, nil
+	case "false", "no", "0", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid bool %q", value)
+}
+
+// matchesExcludes returns false if entry matches any negated predicate,
+// in which case applyAdvancedFilters should drop it.
+func matchesExcludes(entry ReleaseEntry, excludes []QueryExclude) bool {
+	for _, ex := range excludes {
+		switch ex.Field {
+		case "title":
+			if strings.Contains(strings.ToLower(entry.Name), strings.ToLower(ex.Value)) {
+				return false
+			}
+		case "group":
+			if strings.Contains(strings.ToLower(entry.Group), strings.ToLower(ex.Value)) {
+				return false
+			}
+		case "lang":
+			if strings.EqualFold(entry.Language, ex.Value) {
+				return false
+			}
+		case "region":
+			if strings.EqualFold(entry.Region, ex.Value) {
+				return false
+			}
+		case "engine":
+			if strings.EqualFold(entry.Engine, ex.Value) {
+				return false
+			}
+		case "type":
+			if strings.EqualFold(entry.FileType, ex.Value) {
+				return false
+			}
+		case "trainers":
+			if entry.Crack != nil {
+				t := entry.Crack.Trainers
+				if (!ex.HasMin || t >= ex.Min) && (!ex.HasMax || t <= ex.Max) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// matchesFreeTerms checks the DSL's bare (non-field) tokens against
+// Name/Group substring, the same fallback plain search uses. A leading
+// '-' negates the term.
+func matchesFreeTerms(entry ReleaseEntry, terms []string) bool {
+	for _, term := range terms {
+		negate := strings.HasPrefix(term, "-")
+		t := strings.ToLower(strings.TrimPrefix(term, "-"))
+
+		hit := strings.Contains(strings.ToLower(entry.Name), t) ||
+			strings.Contains(strings.ToLower(entry.Group), t)
+
+		if negate && hit {
+			return false
+		}
+		if !negate && !hit {
+			return false
+		}
+	}
+	return true
+}
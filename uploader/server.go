@@ -1,182 +1,226 @@
-// C64 protocol server for C64 client.
-// Simple line-based protocol optimized for low-bandwidth C64 communication.
+// Assembly64 browser command handlers, shared by every protocol dialect
+// (package protocol) so that adding a new command only means registering
+// one handler, not touching each dialect's connection loop.
 package main
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"hash/crc32"
 	"log/slog"
 	"net"
 	"os"
 	"strconv"
 	"strings"
-	"time"
-)
+	"sync"
 
-// C64 protocol commands:
-// CATS                         - List categories
-// LIST <cat> <offset> <n>      - List n entries from category starting at offset
-// SEARCH <off> <n> <query>     - Search all entries (query can be multi-word)
-// SEARCH <off> <n> <cat> <q>   - Search within category (cat=All for all)
-// INFO <id>                    - Get entry details
-// RUN <id>                     - Download and run entry
-// QUIT                         - Close connection
-
-const (
-	c64ReadTimeout = 5 * time.Minute
-	c64PageSize    = 20 // Default entries per page
+	"github.com/tsaarni/c64uploader/uploader/filecache"
+	"github.com/tsaarni/c64uploader/uploader/protocol"
 )
 
-// StartC64Server starts the C64 protocol server.
-func StartC64Server(port int, index *SearchIndex, apiClient *APIClient, assembly64Path string) error {
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-	if err != nil {
-		return fmt.Errorf("failed to start C64 server: %w", err)
-	}
-
-	slog.Info("C64 protocol server listening", "port", port)
-	fmt.Printf("C64 protocol server listening on :%d\n", port)
-
-	go func() {
-		for {
-			conn, err := listener.Accept()
-			if err != nil {
-				slog.Error("Accept error", "error", err)
-				continue
-			}
-			go handleC64Connection(conn, index, apiClient, assembly64Path)
-		}
-	}()
+// downloadChunkSize is the default frame payload size for DOWNLOAD/RESUME,
+// sized for a ~1-2 KB/s C64 modem link.
+const downloadChunkSize = 1024
+
+// sharedFileCache backs readFile when non-nil, avoiding repeated full
+// os.ReadFile calls for the same entry across RUN
+// requests. It is set up once in main via SetFileCache.
+var sharedFileCache *filecache.Cache
+
+// openFiles holds one *filecache.CachedFile per path readFile has cached,
+// so that concurrent RUN/DOWNLOAD requests for the same path share a
+// single CachedFile - and so its per-block locks (see
+// filecache.CachedFile's blockLock) - instead of each building its own
+// and racing the other to fill the same block.
+var (
+	openFilesMu sync.Mutex
+	openFiles   = make(map[string]*filecache.CachedFile)
+)
 
-	return nil
+// SetFileCache installs the block cache used by readFile, discarding any
+// *CachedFile instances cached against the previous one. Passing nil
+// reverts to plain os.ReadFile.
+func SetFileCache(cache *filecache.Cache) {
+	openFilesMu.Lock()
+	defer openFilesMu.Unlock()
+	sharedFileCache = cache
+	openFiles = make(map[string]*filecache.CachedFile)
 }
 
-func handleC64Connection(conn net.Conn, index *SearchIndex, apiClient *APIClient, assembly64Path string) {
-	defer conn.Close()
-
-	remoteAddr := conn.RemoteAddr().String()
-	slog.Info("C64 client connected", "remote", remoteAddr)
+// cachedFileFor returns the shared *filecache.CachedFile for path,
+// creating it on first use.
+func cachedFileFor(path string, size int64) *filecache.CachedFile {
+	openFilesMu.Lock()
+	defer openFilesMu.Unlock()
 
-	// Send greeting
-	conn.Write([]byte("OK Assembly64 Browser\n"))
-
-	reader := bufio.NewReader(conn)
-
-	for {
-		conn.SetReadDeadline(time.Now().Add(c64ReadTimeout))
+	if cf, ok := openFiles[path]; ok {
+		return cf
+	}
 
-		line, err := reader.ReadString('\n')
+	cf := filecache.NewCachedFile(sharedFileCache, path, size, 0, func(offset int64, length int) ([]byte, error) {
+		f, err := os.Open(path)
 		if err != nil {
-			slog.Debug("C64 client disconnected", "remote", remoteAddr, "error", err)
-			return
+			return nil, fmt.Errorf("opening file: %w", err)
 		}
+		defer f.Close()
 
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		slog.Debug("C64 command", "remote", remoteAddr, "cmd", line)
-
-		response := handleC64Command(line, index, apiClient, assembly64Path, conn)
-		if response == "QUIT" {
-			conn.Write([]byte("OK Goodbye\n"))
-			return
+		buf := make([]byte, length)
+		if _, err := f.ReadAt(buf, offset); err != nil {
+			return nil, fmt.Errorf("reading at offset %d: %w", offset, err)
 		}
-		conn.Write([]byte(response))
-	}
+		return buf, nil
+	})
+	openFiles[path] = cf
+	return cf
 }
 
-func handleC64Command(line string, index *SearchIndex, apiClient *APIClient, assembly64Path string, conn net.Conn) string {
-	parts := strings.Fields(line)
-	if len(parts) == 0 {
-		return "ERR Empty command\n"
+// buildRegistry wires the shared command handlers into a protocol.Registry,
+// capturing index/apiClient/assembly64Path in closures. The same registry
+// backs every dialect (C64, native, JSON).
+//
+// streamingAllowed controls whether DOWNLOAD/RESUME/PART write their
+// CRC-framed binary frames straight to conn, bypassing the codec's
+// FormatResponse entirely. That's fine for C64/native, whose wire format
+// is raw bytes to begin with, but it would splice binary into the JSON
+// dialect's newline-delimited stream and desync its parser - so JSON
+// passes false and gets a plain error response for those commands instead.
+func buildRegistry(index *SearchIndex, apiClient *APIClient, assembly64Path string, streamingAllowed bool) protocol.Registry {
+	registry := protocol.Registry{
+		"CATS": func(args []string, conn net.Conn) string {
+			return handleCats(index)
+		},
+		"LIST": func(args []string, conn net.Conn) string {
+			if len(args) < 3 {
+				return "ERR Usage: LIST <category> <offset> <count>\n"
+			}
+			offset, _ := strconv.Atoi(args[1])
+			count, _ := strconv.Atoi(args[2])
+			return handleList(index, args[0], offset, count)
+		},
+		"SEARCH": func(args []string, conn net.Conn) string {
+			if len(args) < 3 {
+				return "ERR Usage: SEARCH <offset> <count> [category] <query>\n"
+			}
+			offset, _ := strconv.Atoi(args[0])
+			count, _ := strconv.Atoi(args[1])
+
+			// The remaining text may start with a known category name
+			// (C64Codec) or may just be the query (NativeCodec).
+			rest := strings.Fields(args[2])
+			category := ""
+			query := args[2]
+			if len(rest) > 0 {
+				for _, cat := range index.CategoryOrder {
+					if strings.EqualFold(cat, rest[0]) {
+						category = cat
+						query = strings.Join(rest[1:], " ")
+						break
+					}
+				}
+			}
+			return handleSearch(index, query, category, offset, count)
+		},
+		"INFO": func(args []string, conn net.Conn) string {
+			if len(args) < 1 {
+				return "ERR Usage: INFO <id>\n"
+			}
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return "ERR Invalid ID\n"
+			}
+			return handleInfo(index, id)
+		},
+		"RUN": func(args []string, conn net.Conn) string {
+			if len(args) < 1 {
+				return "ERR Usage: RUN <id>\n"
+			}
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return "ERR Invalid ID\n"
+			}
+			return handleRun(index, apiClient, assembly64Path, id)
+		},
+		"ADVSEARCH": func(args []string, conn net.Conn) string {
+			// ADVSEARCH offset count key=value key=value ...
+			// Keys: cat, title, group, type, top200
+			if len(args) < 2 {
+				return "ERR Usage: ADVSEARCH <offset> <count> [key=value ...]\n"
+			}
+			offset, _ := strconv.Atoi(args[0])
+			count, _ := strconv.Atoi(args[1])
+			params := make(map[string]string)
+			for _, arg := range args[2:] {
+				if idx := strings.Index(arg, "="); idx > 0 {
+					params[strings.ToLower(arg[:idx])] = arg[idx+1:]
+				}
+			}
+			return handleAdvSearch(index, params, offset, count)
+		},
 	}
 
-	cmd := strings.ToUpper(parts[0])
-
-	switch cmd {
-	case "CATS":
-		return handleCats(index)
-
-	case "LIST":
-		if len(parts) < 4 {
-			return "ERR Usage: LIST <category> <offset> <count>\n"
-		}
-		category := parts[1]
-		offset, _ := strconv.Atoi(parts[2])
-		count, _ := strconv.Atoi(parts[3])
-		return handleList(index, category, offset, count)
-
-	case "SEARCH":
-		if len(parts) < 4 {
-			return "ERR Usage: SEARCH <offset> <count> [category] <query>\n"
-		}
-		offset, _ := strconv.Atoi(parts[1])
-		count, _ := strconv.Atoi(parts[2])
-		// Check if parts[3] is a known category
-		category := ""
-		queryStart := 3
-		potentialCat := parts[3]
-		for _, cat := range index.CategoryOrder {
-			if strings.EqualFold(cat, potentialCat) {
-				category = cat
-				queryStart = 4
-				break
+	if streamingAllowed {
+		registry["DOWNLOAD"] = func(args []string, conn net.Conn) string {
+			// DOWNLOAD <id> [offset] [chunksize]
+			if len(args) < 1 {
+				return "ERR Usage: DOWNLOAD <id> [offset] [chunksize]\n"
 			}
+			id, offset, chunkSize := parseDownloadArgs(args)
+			return handleDownload(index, id, offset, chunkSize).run(conn)
 		}
-		if queryStart > len(parts) {
-			return "ERR Usage: SEARCH <offset> <count> [category] <query>\n"
+		registry["RESUME"] = func(args []string, conn net.Conn) string {
+			// RESUME <id> <offset> [chunksize]
+			if len(args) < 2 {
+				return "ERR Usage: RESUME <id> <offset> [chunksize]\n"
+			}
+			id, offset, chunkSize := parseDownloadArgs(args)
+			return handleDownload(index, id, offset, chunkSize).run(conn)
 		}
-		// Query is all remaining parts joined with spaces
-		query := strings.Join(parts[queryStart:], " ")
-		return handleSearch(index, query, category, offset, count)
-
-	case "INFO":
-		if len(parts) < 2 {
-			return "ERR Usage: INFO <id>\n"
+		registry["PART"] = func(args []string, conn net.Conn) string {
+			if len(args) < 2 {
+				return "ERR Usage: PART <id> <block>\n"
+			}
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return "ERR Invalid ID\n"
+			}
+			block, err := strconv.Atoi(args[1])
+			if err != nil {
+				return "ERR Invalid block\n"
+			}
+			return handlePart(index, id, block, conn)
 		}
-		id, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return "ERR Invalid ID\n"
+	} else {
+		for _, cmd := range []string{"DOWNLOAD", "RESUME", "PART"} {
+			cmd := cmd
+			registry[cmd] = func(args []string, conn net.Conn) string {
+				return fmt.Sprintf("ERR %s streams raw binary frames and isn't supported on this dialect; use the c64 or native port\n", cmd)
+			}
 		}
-		return handleInfo(index, id)
+	}
 
-	case "RUN":
-		if len(parts) < 2 {
-			return "ERR Usage: RUN <id>\n"
-		}
-		id, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return "ERR Invalid ID\n"
-		}
-		return handleRun(index, apiClient, assembly64Path, id)
+	return registry
+}
 
-	case "ADVSEARCH":
-		// ADVSEARCH offset count key=value key=value ...
-		// Keys: cat, title, group, type, top200
-		if len(parts) < 3 {
-			return "ERR Usage: ADVSEARCH <offset> <count> [key=value ...]\n"
-		}
-		offset, _ := strconv.Atoi(parts[1])
-		count, _ := strconv.Atoi(parts[2])
-		// Parse key=value pairs
-		params := make(map[string]string)
-		for i := 3; i < len(parts); i++ {
-			if idx := strings.Index(parts[i], "="); idx > 0 {
-				key := strings.ToLower(parts[i][:idx])
-				value := parts[i][idx+1:]
-				params[key] = value
-			}
-		}
-		return handleAdvSearch(index, params, offset, count)
+// StartC64Server starts the C64 dialect of the protocol server.
+func StartC64Server(port int, index *SearchIndex, apiClient *APIClient, assembly64Path string) error {
+	registry := buildRegistry(index, apiClient, assembly64Path, true)
+	return protocol.NewServer(protocol.C64Codec{}, registry).Start(port)
+}
 
-	case "QUIT":
-		return "QUIT"
+// StartNativeServer starts the native dialect of the protocol server, kept
+// for backward compatibility with existing clients.
+func StartNativeServer(port int, index *SearchIndex, apiClient *APIClient, assembly64Path string) error {
+	registry := buildRegistry(index, apiClient, assembly64Path, true)
+	return protocol.NewServer(protocol.NativeCodec{}, registry).Start(port)
+}
 
-	default:
-		return fmt.Sprintf("ERR Unknown command: %s\n", cmd)
-	}
+// StartJSONServer starts the JSON dialect, handy for debugging with
+// `nc host port | jq`. DOWNLOAD/RESUME/PART are disabled here: they write
+// raw CRC-framed bytes straight to the connection, which would corrupt the
+// newline-delimited JSON stream this dialect promises.
+func StartJSONServer(port int, index *SearchIndex, apiClient *APIClient, assembly64Path string) error {
+	registry := buildRegistry(index, apiClient, assembly64Path, false)
+	return protocol.NewServer(protocol.JSONCodec{}, registry).Start(port)
 }
 
 func handleCats(index *SearchIndex) string {
@@ -389,13 +433,22 @@ func handleRun(index *SearchIndex, apiClient *APIClient, assembly64Path string,
 	var runErr error
 	switch strings.ToLower(entry.FileType) {
 	case "prg":
-		runErr = apiClient.runPRG(fileData)
+		runErr = apiClient.runPRG(context.Background(), fileData, nil)
 	case "crt":
-		runErr = apiClient.runCRT(fileData)
+		runErr = apiClient.runCRT(context.Background(), fileData, nil)
 	case "sid":
-		runErr = apiClient.runSID(fileData)
+		runErr = apiClient.runSID(context.Background(), fileData, nil)
 	case "d64", "g64", "d71", "d81":
-		runErr = apiClient.runDiskImage(fileData, entry.FileType, entry.Name)
+		runErr = apiClient.runDiskImage(context.Background(), fileData, entry.FileType, entry.Name, true, nil)
+	case "t64", "lnx":
+		// Archive containers aren't mountable as disks; extract the
+		// first PRG and run it directly instead.
+		prgData, prgFilename, extractErr := extractFirstProgram(fileData, entry.FileType)
+		if extractErr != nil {
+			return fmt.Sprintf("ERR Extracting PRG: %s\n", extractErr)
+		}
+		slog.Info("Extracted PRG from archive", "container", entry.FileType, "filename", prgFilename, "size", len(prgData))
+		runErr = apiClient.runPRG(context.Background(), prgData, nil)
 	default:
 		return fmt.Sprintf("ERR Unsupported file type: %s\n", entry.FileType)
 	}
@@ -409,7 +462,147 @@ func handleRun(index *SearchIndex, apiClient *APIClient, assembly64Path string,
 	return fmt.Sprintf("OK Running %s\n", entry.Name)
 }
 
-// readFile reads a file from disk.
+// readFile reads an entry's FullPath, which may be a local file (going
+// through the shared block cache when one has been installed via
+// SetFileCache) or, for entries contributed by a remote IndexProvider, a
+// URL fetched directly - there's no local disk to cache against.
 func readFile(path string) ([]byte, error) {
-	return os.ReadFile(path)
+	if isURL(path) {
+		return downloadURL(path)
+	}
+
+	if sharedFileCache == nil {
+		return os.ReadFile(path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat file: %w", err)
+	}
+
+	cached := cachedFileFor(path, info.Size())
+
+	data := make([]byte, cached.Size())
+	if _, err := cached.ReadAt(data, 0); err != nil {
+		return nil, fmt.Errorf("reading cached file: %w", err)
+	}
+	return data, nil
+}
+
+// downloadRequest holds the parsed arguments for DOWNLOAD/RESUME.
+type downloadRequest struct {
+	index     *SearchIndex
+	id        int
+	offset    int
+	chunkSize int
+}
+
+// parseDownloadArgs parses the shared DOWNLOAD/RESUME argument shape:
+// <id> [offset] [chunksize] with offset defaulting to 0.
+func parseDownloadArgs(args []string) (id, offset, chunkSize int) {
+	id, _ = strconv.Atoi(args[0])
+	chunkSize = downloadChunkSize
+
+	if len(args) >= 2 {
+		offset, _ = strconv.Atoi(args[1])
+	}
+	if len(args) >= 3 {
+		if n, err := strconv.Atoi(args[2]); err == nil && n > 0 {
+			chunkSize = n
+		}
+	}
+	return id, offset, chunkSize
+}
+
+// handleDownload streams an entry's raw bytes over conn starting at offset,
+// in chunkSize frames, so a bare C64 with a WiFi modem can fetch files
+// without an Ultimate on the LAN. Frames are CRC16-protected; the whole
+// transfer is also CRC32-checked via the header.
+func handleDownload(index *SearchIndex, id, offset, chunkSize int) downloadRequest {
+	return downloadRequest{index: index, id: id, offset: offset, chunkSize: chunkSize}
+}
+
+// run performs the download, writing frames directly to conn.
+func (r downloadRequest) run(conn net.Conn) string {
+	if r.id < 0 || r.id >= len(r.index.Entries) {
+		return "ERR Invalid ID\n"
+	}
+
+	entry := r.index.Entries[r.id]
+	if entry.FullPath == "" {
+		return "ERR Entry has no file path\n"
+	}
+
+	data, err := readFile(entry.FullPath)
+	if err != nil {
+		return fmt.Sprintf("ERR Cannot read file: %s\n", err)
+	}
+
+	if r.offset < 0 || r.offset > len(data) {
+		return "ERR Invalid offset\n"
+	}
+	data = data[r.offset:]
+
+	fmt.Fprintf(conn, "OK %d %08x\n", len(data), crc32.ChecksumIEEE(data))
+
+	for pos := 0; pos < len(data); pos += r.chunkSize {
+		end := pos + r.chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		frame := data[pos:end]
+
+		fmt.Fprintf(conn, "%04x\n", len(frame))
+		conn.Write(frame)
+		fmt.Fprintf(conn, "%04x\n", crc16CCITT(frame))
+	}
+
+	conn.Write([]byte(".\n"))
+	return ""
+}
+
+// handlePart serves a single 256-byte sector at the given linear block
+// index, for random-access reads of D64 tracks/sectors.
+func handlePart(index *SearchIndex, id, block int, conn net.Conn) string {
+	if id < 0 || id >= len(index.Entries) {
+		return "ERR Invalid ID\n"
+	}
+
+	entry := index.Entries[id]
+	if entry.FullPath == "" {
+		return "ERR Entry has no file path\n"
+	}
+
+	data, err := readFile(entry.FullPath)
+	if err != nil {
+		return fmt.Sprintf("ERR Cannot read file: %s\n", err)
+	}
+
+	offset := block * bytesPerSector
+	if offset < 0 || offset+bytesPerSector > len(data) {
+		return "ERR Invalid block\n"
+	}
+	sector := data[offset : offset+bytesPerSector]
+
+	fmt.Fprintf(conn, "OK %04x\n", len(sector))
+	conn.Write(sector)
+	fmt.Fprintf(conn, "%04x\n", crc16CCITT(sector))
+	return ""
+}
+
+// crc16CCITT computes the CRC-16/CCITT-FALSE checksum used to protect
+// individual DOWNLOAD frames.
+func crc16CCITT(data []byte) uint16 {
+	var crc uint16 = 0xFFFF
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = (crc << 1) ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
 }
@@ -0,0 +1,306 @@
+// Interactive `shell` subcommand: a persistent REPL against a single
+// APIClient, so a user debugging a C64 Ultimate doesn't have to
+// re-establish a host/retry-policy per invocation the way the one-shot
+// poke/load/ftp subcommands require. Commands are kept deliberately
+// close to those subcommands (parsePoke, detectFileType, etc.) so the
+// two surfaces stay consistent.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxShellHistoryEntries caps how many entered commands
+// shell_history.json keeps, mirroring maxHistoryEntries for search
+// history.
+const maxShellHistoryEntries = 200
+
+// loadShellHistory reads shell_history.json from the state directory. A
+// missing file is not an error - it just means there's no history yet.
+func loadShellHistory() []string {
+	var commands []string
+	if err := loadStateFile("shell_history.json", &commands); err != nil {
+		return nil
+	}
+	return commands
+}
+
+// appendShellHistory records command to shell_history.json, dropping the
+// oldest entries once maxShellHistoryEntries is exceeded.
+func appendShellHistory(commands []string, command string) []string {
+	commands = append(commands, command)
+	if len(commands) > maxShellHistoryEntries {
+		commands = commands[len(commands)-maxShellHistoryEntries:]
+	}
+	if err := saveStateFile("shell_history.json", commands); err != nil {
+		slog.Warn("Failed to save shell history", "error", err)
+	}
+	return commands
+}
+
+// parsePeek parses a peek command's argument: either a "<addr>[,<len>]"
+// pair (length defaults to 1) or a "<addr>-<addr>" range such as
+// "$D020-$D02F", both accepting $/0x/decimal numbers.
+func parsePeek(arg string) (address string, length int, err error) {
+	if lo, hi, ok := strings.Cut(arg, "-"); ok {
+		loVal, err := parseNumber(strings.TrimSpace(lo))
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid range start: %w", err)
+		}
+		hiVal, err := parseNumber(strings.TrimSpace(hi))
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid range end: %w", err)
+		}
+		if hiVal < loVal {
+			return "", 0, fmt.Errorf("range end %s is before start %s", hi, lo)
+		}
+		return fmt.Sprintf("%x", loVal), hiVal - loVal + 1, nil
+	}
+
+	addrStr, lenStr, hasLen := strings.Cut(arg, ",")
+	addrVal, err := parseNumber(strings.TrimSpace(addrStr))
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid address: %w", err)
+	}
+
+	length = 1
+	if hasLen {
+		length, err = parseNumber(strings.TrimSpace(lenStr))
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid length: %w", err)
+		}
+	}
+
+	return fmt.Sprintf("%x", addrVal), length, nil
+}
+
+// renderHexDump formats data as a classic hex+ASCII dump, 16 bytes per
+// row, with rows labeled by address starting at startAddr.
+func renderHexDump(data []byte, startAddr int) string {
+	var b strings.Builder
+	for offset := 0; offset < len(data); offset += 16 {
+		end := offset + 16
+		if end > len(data) {
+			end = len(data)
+		}
+		row := data[offset:end]
+
+		fmt.Fprintf(&b, "%04X:", startAddr+offset)
+		for i := 0; i < 16; i++ {
+			if i < len(row) {
+				fmt.Fprintf(&b, " %02X", row[i])
+			} else {
+				b.WriteString("   ")
+			}
+		}
+		b.WriteString("  |")
+		for _, c := range row {
+			if c >= 0x20 && c < 0x7f {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteString("|\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// runShellCommand executes a single shell line against client, printing
+// its result or error. It returns false when the line requests the shell
+// exit (quit/exit).
+func runShellCommand(ctx context.Context, client *APIClient, line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return true
+	}
+	cmd, rest := fields[0], strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+
+	switch strings.ToLower(cmd) {
+	case "quit", "exit":
+		return false
+
+	case "help", "?":
+		fmt.Println("Commands:")
+		fmt.Println("  poke <addr>,<value>       Write a byte to C64 memory")
+		fmt.Println("  peek <addr>[,<len>]       Read and hex-dump C64 memory")
+		fmt.Println("  peek <addr>-<addr>        Read and hex-dump an address range")
+		fmt.Println("  reset                     Reset the C64")
+		fmt.Println("  reboot                    Reboot the C64 Ultimate")
+		fmt.Println("  load <file|url>           Upload and run a file")
+		fmt.Println("  mount <disk>              Upload a disk image and mount it")
+		fmt.Println("  type \"<text>\"             Type text on the C64 keyboard")
+		fmt.Println("  quit, exit                Leave the shell")
+
+	case "poke":
+		parts := strings.SplitN(rest, ",", 2)
+		if len(parts) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: usage: poke <addr>,<value>")
+			break
+		}
+		addressHex, value, err := parsePoke(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		if err := client.WriteMemory(ctx, addressHex, []byte{value}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		fmt.Printf("POKE %s,%d OK\n", parts[0], value)
+
+	case "peek":
+		if rest == "" {
+			fmt.Fprintln(os.Stderr, "Error: usage: peek <addr>[,<len>] or peek <addr>-<addr>")
+			break
+		}
+		addressHex, length, err := parsePeek(rest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		data, err := client.ReadMemory(ctx, addressHex, length)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		startAddr, _ := strconv.ParseInt(addressHex, 16, 64)
+		fmt.Println(renderHexDump(data, int(startAddr)))
+
+	case "reset":
+		if err := client.resetMachine(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		fmt.Println("Reset OK")
+
+	case "reboot":
+		if err := client.rebootMachine(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		fmt.Println("Reboot OK")
+
+	case "load":
+		if rest == "" {
+			fmt.Fprintln(os.Stderr, "Error: usage: load <file|url>")
+			break
+		}
+		var fileData []byte
+		var err error
+		if isURL(rest) {
+			fileData, err = downloadURL(rest)
+		} else {
+			fileData, err = os.ReadFile(rest)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		if err := uploadAndRunFile(ctx, client, fileData, rest, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		fmt.Println("Load OK")
+
+	case "mount":
+		if rest == "" {
+			fmt.Fprintln(os.Stderr, "Error: usage: mount <disk>")
+			break
+		}
+		fileType := detectFileType(rest)
+		isDisk := false
+		switch fileType {
+		case "d64", "d71", "d81", "g64", "g71":
+			isDisk = true
+		}
+		if !isDisk {
+			fmt.Fprintln(os.Stderr, "Error: unsupported disk type (supported: .d64, .d71, .d81, .g64, .g71)")
+			break
+		}
+		var diskData []byte
+		var err error
+		if isURL(rest) {
+			diskData, err = downloadURL(rest)
+		} else {
+			diskData, err = os.ReadFile(rest)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		remotePath, err := client.uploadDiskViaFTPDeduped(ctx, diskData, filepath.Base(rest), nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		if err := client.mountDisk(ctx, remotePath, fileType); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		fmt.Println("Mount OK")
+
+	case "type":
+		text := strings.TrimSpace(rest)
+		text = strings.TrimPrefix(text, `"`)
+		text = strings.TrimSuffix(text, `"`)
+		if text == "" {
+			fmt.Fprintln(os.Stderr, `Error: usage: type "<text>"`)
+			break
+		}
+		if err := client.injectKeyboardCommand(ctx, text); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			break
+		}
+		fmt.Println("Type OK")
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown command '%s' (try 'help')\n", cmd)
+	}
+
+	return true
+}
+
+func runShell(args []string) {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	host := fs.String("host", "c64u", "C64 Ultimate hostname or IP address")
+	verbose := fs.Bool("v", false, "Enable verbose debug logging")
+	retryPolicy := addRetryFlags(fs)
+	fs.Parse(args)
+
+	if *verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	}
+
+	client := NewAPIClient(*host)
+	client.RetryPolicy = retryPolicy()
+	ctx := context.Background()
+
+	history := loadShellHistory()
+
+	fmt.Printf("c64uploader shell - connected to %s (type 'help' for commands, 'quit' to exit)\n", *host)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("c64> ")
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		history = appendShellHistory(history, line)
+		if !runShellCommand(ctx, client, line) {
+			break
+		}
+	}
+}
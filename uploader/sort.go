@@ -0,0 +1,128 @@
+// Column sorting for the TUI results list, in the spirit of a
+// directory-listing UI's Name/Size/Modified sort options. Assembly64
+// collections routinely run to thousands of entries per category, so
+// browsing purely in on-disk order makes the tool hard to use; the s/r
+// keys (see Model.handleNormalKeyMsg in tui.go) cycle the column and
+// flip ascending/descending. The choice is persisted to sort.json next
+// to the JSON database so it survives restarts.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// sortField selects which ReleaseEntry column filteredResults are
+// ordered by. Only consulted outside fuzzy mode - fuzzy mode ranks by
+// match relevance instead (see sortCandidates).
+type sortField int
+
+const (
+	sortByName sortField = iota
+	sortByGroup
+	sortByYear
+	sortByFileType
+	sortByCategory
+	sortFieldCount // Sentinel for field count.
+)
+
+// String returns the column label shown in renderHeader's sort
+// indicator (e.g. "Sort: Year").
+func (f sortField) String() string {
+	switch f {
+	case sortByName:
+		return "Name"
+	case sortByGroup:
+		return "Group"
+	case sortByYear:
+		return "Year"
+	case sortByFileType:
+		return "Type"
+	case sortByCategory:
+		return "Category"
+	default:
+		return "Name"
+	}
+}
+
+// sortArrow returns the direction glyph shown next to the sort field in
+// renderHeader and renderResults.
+func sortArrow(descending bool) string {
+	if descending {
+		return "↓"
+	}
+	return "↑"
+}
+
+// sortValue extracts the column value that field sorts entry by.
+func sortValue(field sortField, entry ReleaseEntry) string {
+	switch field {
+	case sortByGroup:
+		return entry.Group
+	case sortByYear:
+		return entry.Year
+	case sortByFileType:
+		return entry.FileType
+	case sortByCategory:
+		return entry.CategoryName
+	default:
+		return entry.Name
+	}
+}
+
+// sortCandidatesByField orders candidates by the column field extracts
+// from entries, the sort used outside fuzzy mode.
+func sortCandidatesByField(candidates []filterCandidate, entries []ReleaseEntry, field sortField, descending bool) {
+	sort.SliceStable(candidates, func(a, b int) bool {
+		va := sortValue(field, entries[candidates[a].idx])
+		vb := sortValue(field, entries[candidates[b].idx])
+		if descending {
+			return va > vb
+		}
+		return va < vb
+	})
+}
+
+// sortState is sort.json's on-disk shape.
+type sortState struct {
+	Field      sortField `json:"field"`
+	Descending bool      `json:"descending"`
+}
+
+// sortStatePath returns sort.json's path next to the JSON database file
+// at dbPath, e.g. "games.json" -> "sort.json" in the same directory.
+func sortStatePath(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "sort.json")
+}
+
+// loadSortState reads the persisted sort field/order from next to
+// dbPath. A missing or malformed file is not an error - it just means
+// the default sort (Name, ascending) is used.
+func loadSortState(dbPath string) (sortField, bool) {
+	data, err := os.ReadFile(sortStatePath(dbPath))
+	if err != nil {
+		return sortByName, false
+	}
+
+	var state sortState
+	if err := json.Unmarshal(data, &state); err != nil || state.Field < 0 || state.Field >= sortFieldCount {
+		return sortByName, false
+	}
+	return state.Field, state.Descending
+}
+
+// saveSortState writes the sort field/order next to dbPath so it's
+// restored on the next run.
+func saveSortState(dbPath string, field sortField, descending bool) error {
+	data, err := json.MarshalIndent(sortState{Field: field, Descending: descending}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sort state: %w", err)
+	}
+	if err := os.WriteFile(sortStatePath(dbPath), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write sort state: %w", err)
+	}
+	return nil
+}
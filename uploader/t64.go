@@ -0,0 +1,103 @@
+// T64 tape archive parsing and PRG extraction.
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	t64HeaderSize   = 64
+	t64DirEntrySize = 32
+
+	t64EntryTypeNormal = 1
+)
+
+// t64Entry is one directory entry in a T64 archive.
+type t64Entry struct {
+	entryType byte
+	fileType  byte
+	startAddr uint16
+	endAddr   uint16
+	offset    uint32
+	filename  string
+}
+
+// parseT64Directory parses the T64 header and directory, returning every
+// entry in archive order.
+func parseT64Directory(data []byte) ([]t64Entry, error) {
+	if len(data) < t64HeaderSize {
+		return nil, fmt.Errorf("T64 file too small: %d bytes", len(data))
+	}
+
+	usedEntries := binary.LittleEndian.Uint16(data[36:38])
+	if usedEntries == 0 {
+		return nil, fmt.Errorf("T64 archive has no entries")
+	}
+
+	var entries []t64Entry
+	for i := 0; i < int(usedEntries); i++ {
+		offset := t64HeaderSize + i*t64DirEntrySize
+		if offset+t64DirEntrySize > len(data) {
+			break
+		}
+		raw := data[offset : offset+t64DirEntrySize]
+
+		name := strings.TrimRight(string(raw[16:32]), " \x00\xa0")
+
+		entries = append(entries, t64Entry{
+			entryType: raw[0],
+			fileType:  raw[1],
+			startAddr: binary.LittleEndian.Uint16(raw[2:4]),
+			endAddr:   binary.LittleEndian.Uint16(raw[4:6]),
+			offset:    binary.LittleEndian.Uint32(raw[8:12]),
+			filename:  name,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("T64 archive has no readable entries")
+	}
+	return entries, nil
+}
+
+// extractT64Entry extracts entry's payload from t64Data, prefixed with its
+// C64 load address like a regular PRG.
+func extractT64Entry(t64Data []byte, entry t64Entry) ([]byte, error) {
+	length := int(entry.endAddr) - int(entry.startAddr)
+	if length <= 0 {
+		return nil, fmt.Errorf("T64 entry %q has an invalid length", entry.filename)
+	}
+	start := int(entry.offset)
+	end := start + length
+	if start < 0 || end > len(t64Data) {
+		return nil, fmt.Errorf("T64 entry %q extends past end of file", entry.filename)
+	}
+
+	prg := make([]byte, 0, 2+length)
+	prg = append(prg, byte(entry.startAddr), byte(entry.startAddr>>8))
+	prg = append(prg, t64Data[start:end]...)
+	return prg, nil
+}
+
+// extractFirstPRGFromT64 extracts the first normal file entry from a T64 archive.
+func extractFirstPRGFromT64(t64Data []byte) ([]byte, string, error) {
+	entries, err := parseT64Directory(t64Data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, entry := range entries {
+		if entry.entryType != t64EntryTypeNormal {
+			continue
+		}
+		prg, err := extractT64Entry(t64Data, entry)
+		if err != nil {
+			continue
+		}
+		return prg, entry.filename, nil
+	}
+
+	return nil, "", fmt.Errorf("no loadable entries found in T64 archive")
+}
@@ -0,0 +1,283 @@
+// Pluggable color themes for the TUI. A Theme names a lipgloss.Style for
+// every role the TUI renders with (title, header, selected row, ...).
+// Built-in themes are registered in code; user themes are discovered
+// under $XDG_CONFIG_HOME/c64uploader/themes/*.yaml (or .json) and
+// declare the same roles. The T key (see Model.cycleTheme in tui.go)
+// cycles through whatever is installed, built-ins and user themes alike.
+// Mirrors fx's theme system (theme.ThemeTester, --themes).
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// styleSpec is a theme role's on-disk representation. Foreground and
+// Background accept any lipgloss color spec: an ANSI index ("205") or a
+// hex string ("#ff00ff").
+type styleSpec struct {
+	Foreground string `json:"foreground" yaml:"foreground"`
+	Background string `json:"background" yaml:"background"`
+	Bold       bool   `json:"bold" yaml:"bold"`
+	Reverse    bool   `json:"reverse" yaml:"reverse"`
+}
+
+// style converts a styleSpec into a lipgloss.Style.
+func (s styleSpec) style() lipgloss.Style {
+	st := lipgloss.NewStyle()
+	if s.Foreground != "" {
+		st = st.Foreground(lipgloss.Color(s.Foreground))
+	}
+	if s.Background != "" {
+		st = st.Background(lipgloss.Color(s.Background))
+	}
+	if s.Bold {
+		st = st.Bold(true)
+	}
+	if s.Reverse {
+		st = st.Reverse(true)
+	}
+	return st
+}
+
+// themeSpec is a theme's on-disk JSON/YAML shape: a name plus one
+// styleSpec per role. Field names match the role names used by Theme.
+type themeSpec struct {
+	Name string `json:"name" yaml:"name"`
+
+	Title         styleSpec `json:"title" yaml:"title"`
+	Header        styleSpec `json:"header" yaml:"header"`
+	Selected      styleSpec `json:"selected" yaml:"selected"`
+	Category      styleSpec `json:"category" yaml:"category"`
+	Dim           styleSpec `json:"dim" yaml:"dim"`
+	Help          styleSpec `json:"help" yaml:"help"`
+	Status        styleSpec `json:"status" yaml:"status"`
+	Error         styleSpec `json:"error" yaml:"error"`
+	Cursor        styleSpec `json:"cursor" yaml:"cursor"`
+	FormLabel     styleSpec `json:"formLabel" yaml:"formLabel"`
+	FormInput     styleSpec `json:"formInput" yaml:"formInput"`
+	FormActive    styleSpec `json:"formActive" yaml:"formActive"`
+	FormToggleOn  styleSpec `json:"formToggleOn" yaml:"formToggleOn"`
+	FormToggleOff styleSpec `json:"formToggleOff" yaml:"formToggleOff"`
+	Match         styleSpec `json:"match" yaml:"match"`
+}
+
+// Theme is a named collection of lipgloss.Style, one per role the TUI
+// renders with. Build one from a themeSpec with newTheme.
+type Theme struct {
+	Name string
+
+	Title         lipgloss.Style
+	Header        lipgloss.Style
+	Selected      lipgloss.Style
+	Category      lipgloss.Style
+	Dim           lipgloss.Style
+	Help          lipgloss.Style
+	Status        lipgloss.Style
+	Error         lipgloss.Style
+	Cursor        lipgloss.Style
+	FormLabel     lipgloss.Style
+	FormInput     lipgloss.Style
+	FormActive    lipgloss.Style
+	FormToggleOn  lipgloss.Style
+	FormToggleOff lipgloss.Style
+	Match         lipgloss.Style
+}
+
+// newTheme compiles a themeSpec's styleSpecs into a Theme, applying the
+// handful of structural (non-color) properties the original hardcoded
+// styles carried - margins on the title/help lines and a fixed label
+// column width in the advanced search form - which aren't meant to vary
+// between themes.
+func newTheme(spec themeSpec) Theme {
+	return Theme{
+		Name:          spec.Name,
+		Title:         spec.Title.style().MarginBottom(1),
+		Header:        spec.Header.style(),
+		Selected:      spec.Selected.style(),
+		Category:      spec.Category.style(),
+		Dim:           spec.Dim.style(),
+		Help:          spec.Help.style().MarginTop(1),
+		Status:        spec.Status.style(),
+		Error:         spec.Error.style(),
+		Cursor:        spec.Cursor.style(),
+		FormLabel:     spec.FormLabel.style().Width(14),
+		FormInput:     spec.FormInput.style(),
+		FormActive:    spec.FormActive.style(),
+		FormToggleOn:  spec.FormToggleOn.style(),
+		FormToggleOff: spec.FormToggleOff.style(),
+		Match:         spec.Match.style(),
+	}
+}
+
+// builtinThemeSpecs are the themes shipped in-code. "default" is always
+// first so it loads when no --theme flag or user themes are present.
+var builtinThemeSpecs = []themeSpec{
+	{
+		Name:          "default",
+		Title:         styleSpec{Foreground: "205", Bold: true},
+		Header:        styleSpec{Foreground: "39", Bold: true},
+		Selected:      styleSpec{Foreground: "170", Bold: true},
+		Category:      styleSpec{Foreground: "86"},
+		Dim:           styleSpec{Foreground: "241"},
+		Help:          styleSpec{Foreground: "241"},
+		Status:        styleSpec{Foreground: "205"},
+		Error:         styleSpec{Foreground: "196", Bold: true},
+		Cursor:        styleSpec{Bold: true, Reverse: true},
+		FormLabel:     styleSpec{Foreground: "39"},
+		FormInput:     styleSpec{Foreground: "255"},
+		FormActive:    styleSpec{Foreground: "170", Bold: true},
+		FormToggleOn:  styleSpec{Foreground: "82", Bold: true},
+		FormToggleOff: styleSpec{Foreground: "241"},
+		Match:         styleSpec{Foreground: "220", Bold: true},
+	},
+	{
+		Name:          "monochrome",
+		Title:         styleSpec{Foreground: "255", Bold: true},
+		Header:        styleSpec{Foreground: "255", Bold: true},
+		Selected:      styleSpec{Reverse: true},
+		Category:      styleSpec{Foreground: "250"},
+		Dim:           styleSpec{Foreground: "240"},
+		Help:          styleSpec{Foreground: "240"},
+		Status:        styleSpec{Foreground: "255"},
+		Error:         styleSpec{Foreground: "255", Bold: true, Reverse: true},
+		Cursor:        styleSpec{Bold: true, Reverse: true},
+		FormLabel:     styleSpec{Foreground: "255"},
+		FormInput:     styleSpec{Foreground: "255"},
+		FormActive:    styleSpec{Reverse: true},
+		FormToggleOn:  styleSpec{Foreground: "255", Bold: true},
+		FormToggleOff: styleSpec{Foreground: "240"},
+		Match:         styleSpec{Bold: true, Reverse: true},
+	},
+	{
+		Name:          "high-contrast",
+		Title:         styleSpec{Foreground: "226", Bold: true},
+		Header:        styleSpec{Foreground: "51", Bold: true},
+		Selected:      styleSpec{Foreground: "0", Background: "226", Bold: true},
+		Category:      styleSpec{Foreground: "46"},
+		Dim:           styleSpec{Foreground: "250"},
+		Help:          styleSpec{Foreground: "250"},
+		Status:        styleSpec{Foreground: "226"},
+		Error:         styleSpec{Foreground: "0", Background: "196", Bold: true},
+		Cursor:        styleSpec{Bold: true, Reverse: true},
+		FormLabel:     styleSpec{Foreground: "51"},
+		FormInput:     styleSpec{Foreground: "255"},
+		FormActive:    styleSpec{Foreground: "0", Background: "226", Bold: true},
+		FormToggleOn:  styleSpec{Foreground: "0", Background: "46", Bold: true},
+		FormToggleOff: styleSpec{Foreground: "250"},
+		Match:         styleSpec{Foreground: "0", Background: "226", Bold: true},
+	},
+	{
+		// The classic light-blue-on-blue C64 screen.
+		Name:          "c64-classic",
+		Title:         styleSpec{Foreground: "153", Bold: true},
+		Header:        styleSpec{Foreground: "153", Bold: true},
+		Selected:      styleSpec{Foreground: "20", Background: "153", Bold: true},
+		Category:      styleSpec{Foreground: "153"},
+		Dim:           styleSpec{Foreground: "60"},
+		Help:          styleSpec{Foreground: "60"},
+		Status:        styleSpec{Foreground: "153"},
+		Error:         styleSpec{Foreground: "211", Bold: true},
+		Cursor:        styleSpec{Bold: true, Reverse: true},
+		FormLabel:     styleSpec{Foreground: "153"},
+		FormInput:     styleSpec{Foreground: "189"},
+		FormActive:    styleSpec{Foreground: "20", Background: "153", Bold: true},
+		FormToggleOn:  styleSpec{Foreground: "189", Bold: true},
+		FormToggleOff: styleSpec{Foreground: "60"},
+		Match:         styleSpec{Foreground: "211", Bold: true},
+	},
+}
+
+// themesDir returns $XDG_CONFIG_HOME/c64uploader/themes (or the
+// platform's equivalent user config directory).
+func themesDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "c64uploader", "themes"), nil
+}
+
+// loadUserThemeSpecs reads every *.yaml and *.json file in the themes
+// directory. A missing directory is not an error - it just means no
+// user themes are installed. A malformed file is logged and skipped
+// rather than failing the whole load.
+func loadUserThemeSpecs() []themeSpec {
+	dir, err := themesDir()
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, pattern := range []string{"*.yaml", "*.yml", "*.json"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		paths = append(paths, matches...)
+	}
+
+	var specs []themeSpec
+	for _, path := range paths {
+		spec, err := loadThemeSpecFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping theme %s: %v\n", path, err)
+			continue
+		}
+		if spec.Name == "" {
+			spec.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// loadThemeSpecFile parses a single theme file, dispatching on extension.
+func loadThemeSpecFile(path string) (themeSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return themeSpec{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var spec themeSpec
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &spec)
+	default: // .yaml, .yml
+		err = yaml.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return themeSpec{}, fmt.Errorf("failed to parse: %w", err)
+	}
+	return spec, nil
+}
+
+// loadThemes returns every installed theme - the built-ins followed by
+// any user themes found on disk - compiled and ready to apply.
+func loadThemes() []Theme {
+	specs := append([]themeSpec{}, builtinThemeSpecs...)
+	specs = append(specs, loadUserThemeSpecs()...)
+
+	themes := make([]Theme, len(specs))
+	for i, spec := range specs {
+		themes[i] = newTheme(spec)
+	}
+	return themes
+}
+
+// findTheme returns the index of the theme named name (case-insensitive)
+// within themes, or 0 if there is no match.
+func findTheme(themes []Theme, name string) int {
+	if name == "" {
+		return 0
+	}
+	for i, t := range themes {
+		if strings.EqualFold(t.Name, name) {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,381 @@
+// FileTransport generalizes the FTP-only upload path (APIClient's old
+// ftpConnect/ftpUpload, now folded into ftpTransport below) so push and
+// disk-image uploads can target any network share the C64 Ultimate has
+// mounted over its USB/SD slot, not just its built-in FTP server.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// FileTransport uploads a file to a C64 Ultimate-reachable destination.
+// Exists lets a batch push skip files already present on the remote,
+// modeled on ficsit-cli's existsWithLock: try the cheap path first (MLST
+// for FTP, Stat for SFTP, HEAD for WebDAV) and fall back when the server
+// doesn't support it.
+type FileTransport interface {
+	Exists(ctx context.Context, destination string) (bool, error)
+	Upload(ctx context.Context, fileData []byte, destination string, progress progressFunc) error
+	Close() error
+}
+
+// transportScheme identifies which FileTransport implementation a push
+// destination selects.
+type transportScheme string
+
+const (
+	transportFTP    transportScheme = "ftp"
+	transportSFTP   transportScheme = "sftp"
+	transportWebDAV transportScheme = "webdav"
+)
+
+// detectTransportScheme infers a push destination's transport from a
+// URL scheme prefix (e.g. "sftp://c64u/Temp/x.prg"), defaulting to FTP
+// for a bare host:path destination - c64uploader's original upload path.
+func detectTransportScheme(destination string) (transportScheme, string) {
+	switch {
+	case strings.HasPrefix(destination, "sftp://"):
+		return transportSFTP, strings.TrimPrefix(destination, "sftp://")
+	case strings.HasPrefix(destination, "webdav://"):
+		return transportWebDAV, strings.TrimPrefix(destination, "webdav://")
+	case strings.HasPrefix(destination, "ftp://"):
+		return transportFTP, strings.TrimPrefix(destination, "ftp://")
+	default:
+		return transportFTP, destination
+	}
+}
+
+// transportCredentials is a resolved username/password pair for a push
+// destination host.
+type transportCredentials struct {
+	Username string
+	Password string
+}
+
+// resolveCredentials looks up login credentials for host: the
+// C64UPLOADER_USER/C64UPLOADER_PASSWORD environment variables first,
+// then ~/.netrc (the same file curl, ftp and git read), finally falling
+// back to the anonymous/anonymous login the C64 Ultimate's FTP server
+// accepts out of the box.
+func resolveCredentials(host string) transportCredentials {
+	if user := os.Getenv("C64UPLOADER_USER"); user != "" {
+		return transportCredentials{Username: user, Password: os.Getenv("C64UPLOADER_PASSWORD")}
+	}
+	if creds, ok := netrcCredentials(host); ok {
+		return creds
+	}
+	return transportCredentials{Username: "anonymous", Password: "anonymous"}
+}
+
+// netrcCredentials looks up host's "machine" stanza in ~/.netrc,
+// returning ok=false if the file, or a matching machine entry, doesn't
+// exist.
+func netrcCredentials(host string) (transportCredentials, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return transportCredentials{}, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".netrc"))
+	if err != nil {
+		return transportCredentials{}, false
+	}
+
+	fields := strings.Fields(string(data))
+	var creds transportCredentials
+	matched := false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if i+1 < len(fields) {
+				matched = fields[i+1] == host
+				i++
+			}
+		case "login":
+			if matched && i+1 < len(fields) {
+				creds.Username = fields[i+1]
+				i++
+			}
+		case "password":
+			if matched && i+1 < len(fields) {
+				creds.Password = fields[i+1]
+				i++
+			}
+		}
+	}
+	if creds.Username == "" {
+		return transportCredentials{}, false
+	}
+	return creds, true
+}
+
+// newFileTransport connects to host using scheme's default port and
+// credentials resolved via resolveCredentials. insecureHostKey disables SFTP
+// host-key verification and is ignored by the other schemes.
+func newFileTransport(ctx context.Context, scheme transportScheme, host string, insecureHostKey bool) (FileTransport, error) {
+	creds := resolveCredentials(host)
+
+	switch scheme {
+	case transportSFTP:
+		return newSFTPTransport(ctx, host+":22", creds, insecureHostKey)
+	case transportWebDAV:
+		return newWebDAVTransport(host), nil
+	default:
+		return newFTPTransport(ctx, host+":21", creds)
+	}
+}
+
+// ftpTransport is the FileTransport c64uploader has always used: the
+// C64 Ultimate's built-in FTP server.
+type ftpTransport struct {
+	conn *ftp.ServerConn
+}
+
+// newFTPTransport dials and logs into addr. The underlying FTP library
+// predates context support, so ctx is only checked before dialing - it
+// can't interrupt a dial already in flight.
+func newFTPTransport(ctx context.Context, addr string, creds transportCredentials) (*ftpTransport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	conn, err := ftp.Dial(addr, ftp.DialWithTimeout(30*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to FTP server: %w", err)
+	}
+	if err := conn.Login(creds.Username, creds.Password); err != nil {
+		conn.Quit()
+		return nil, fmt.Errorf("FTP login failed: %w", err)
+	}
+	return &ftpTransport{conn: conn}, nil
+}
+
+// Exists reports whether destination is present, trying MLST (via
+// GetEntry) first and falling back to listing its parent directory for
+// servers that don't support MLST.
+func (t *ftpTransport) Exists(ctx context.Context, destination string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if entry, err := t.conn.GetEntry(destination); err == nil {
+		return entry != nil, nil
+	}
+
+	entries, err := t.conn.List(path.Dir(destination))
+	if err != nil {
+		return false, nil
+	}
+	name := path.Base(destination)
+	for _, e := range entries {
+		if e.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Upload stores fileData at destination over the connected FTP session.
+func (t *ftpTransport) Upload(ctx context.Context, fileData []byte, destination string, progress progressFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var reader io.Reader = bytes.NewReader(fileData)
+	if progress != nil {
+		reader = &countingReader{r: reader, total: len(fileData), progress: progress}
+	}
+
+	if err := t.conn.Stor(destination, reader); err != nil {
+		return fmt.Errorf("FTP upload failed: %w", err)
+	}
+	return nil
+}
+
+func (t *ftpTransport) Close() error {
+	return t.conn.Quit()
+}
+
+// sftpTransport targets a network share mounted on the C64 Ultimate's
+// USB/SD slot and reachable over SSH/SFTP.
+type sftpTransport struct {
+	sshClient *ssh.Client
+	client    *sftp.Client
+}
+
+// newSFTPTransport dials and authenticates addr over SSH, then opens an
+// SFTP session on top of it. Host keys are checked against
+// ~/.ssh/known_hosts, the same file OpenSSH itself trusts on first
+// connection, unless insecureHostKey opts out (the C64 Ultimate has no
+// published host key to pin, so some users will never have it recorded).
+func newSFTPTransport(ctx context.Context, addr string, creds transportCredentials, insecureHostKey bool) (*sftpTransport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := sftpHostKeyCallback(insecureHostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            creds.Username,
+		Auth:            []ssh.AuthMethod{ssh.Password(creds.Password)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         30 * time.Second,
+	}
+
+	sshClient, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to SFTP server: %w", err)
+	}
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf("starting SFTP session: %w", err)
+	}
+
+	return &sftpTransport{sshClient: sshClient, client: client}, nil
+}
+
+// sftpHostKeyCallback builds the ssh.ClientConfig.HostKeyCallback for an
+// SFTP connection: normally a knownhosts lookup against ~/.ssh/known_hosts,
+// or ssh.InsecureIgnoreHostKey() when the caller has explicitly opted into
+// skipping verification via -insecure-host-key.
+func sftpHostKeyCallback(insecureHostKey bool) (ssh.HostKeyCallback, error) {
+	if insecureHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating known_hosts file: %w", err)
+	}
+
+	callback, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("loading ~/.ssh/known_hosts (pass -insecure-host-key to skip verification): %w", err)
+	}
+	return callback, nil
+}
+
+func (t *sftpTransport) Exists(ctx context.Context, destination string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if _, err := t.client.Stat(destination); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("SFTP stat failed: %w", err)
+	}
+	return true, nil
+}
+
+func (t *sftpTransport) Upload(ctx context.Context, fileData []byte, destination string, progress progressFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f, err := t.client.Create(destination)
+	if err != nil {
+		return fmt.Errorf("creating remote file: %w", err)
+	}
+	defer f.Close()
+
+	var reader io.Reader = bytes.NewReader(fileData)
+	if progress != nil {
+		reader = &countingReader{r: reader, total: len(fileData), progress: progress}
+	}
+
+	if _, err := io.Copy(f, reader); err != nil {
+		return fmt.Errorf("SFTP upload failed: %w", err)
+	}
+	return nil
+}
+
+func (t *sftpTransport) Close() error {
+	t.client.Close()
+	return t.sshClient.Close()
+}
+
+// webdavTransport targets a WebDAV share mounted on the C64 Ultimate,
+// using plain HTTP PUT/HEAD rather than a full WebDAV client - the
+// C64 Ultimate's WebDAV support is read/write-file only, so there's
+// nothing here that needs PROPFIND/locking.
+type webdavTransport struct {
+	baseURL string
+	creds   transportCredentials
+	client  *http.Client
+}
+
+func newWebDAVTransport(host string) *webdavTransport {
+	return &webdavTransport{
+		baseURL: "http://" + host,
+		creds:   resolveCredentials(host),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (t *webdavTransport) Exists(ctx context.Context, destination string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, t.baseURL+destination, nil)
+	if err != nil {
+		return false, fmt.Errorf("building WebDAV request: %w", err)
+	}
+	if t.creds.Username != "" {
+		req.SetBasicAuth(t.creds.Username, t.creds.Password)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("WebDAV exists check failed: %w", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+func (t *webdavTransport) Upload(ctx context.Context, fileData []byte, destination string, progress progressFunc) error {
+	var reader io.Reader = bytes.NewReader(fileData)
+	if progress != nil {
+		reader = &countingReader{r: reader, total: len(fileData), progress: progress}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, t.baseURL+destination, reader)
+	if err != nil {
+		return fmt.Errorf("building WebDAV request: %w", err)
+	}
+	req.ContentLength = int64(len(fileData))
+	if t.creds.Username != "" {
+		req.SetBasicAuth(t.creds.Username, t.creds.Password)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("WebDAV upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("WebDAV upload failed: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (t *webdavTransport) Close() error {
+	return nil
+}
@@ -3,9 +3,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -19,6 +22,10 @@ type searchMode int
 const (
 	modeNormal searchMode = iota
 	modeAdvanced
+	modeHistoryPicker
+	modeSavedQueryPicker
+	modeSaveQueryPrompt
+	modeDiskBrowser
 )
 
 // advancedField represents a field in the advanced search form.
@@ -41,59 +48,68 @@ const (
 	fieldCount // Sentinel for field count.
 )
 
+// Rendering style for every role the TUI draws with. These used to be
+// hardcoded lipgloss.Style literals; they're now populated from a Theme
+// (see theme.go) by applyTheme, which NewModel calls with the initial
+// theme and cycleTheme calls again each time the user presses T.
 var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("205")).
-			MarginBottom(1)
-
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("39"))
-
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("170")).
-			Bold(true)
-
-	categoryStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("86"))
-
-	dimStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241"))
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			MarginTop(1)
-
-	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("205"))
-
-	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			Bold(true)
-
-	cursorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Reverse(true)
-
-	formLabelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("39")).
-			Width(14)
-
-	formInputStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("255"))
-
-	formActiveStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("170")).
-			Bold(true)
+	titleStyle         lipgloss.Style
+	headerStyle        lipgloss.Style
+	selectedStyle      lipgloss.Style
+	categoryStyle      lipgloss.Style
+	dimStyle           lipgloss.Style
+	helpStyle          lipgloss.Style
+	statusStyle        lipgloss.Style
+	errorStyle         lipgloss.Style
+	cursorStyle        lipgloss.Style
+	formLabelStyle     lipgloss.Style
+	formInputStyle     lipgloss.Style
+	formActiveStyle    lipgloss.Style
+	formToggleOnStyle  lipgloss.Style
+	formToggleOffStyle lipgloss.Style
+	matchStyle         lipgloss.Style
+)
 
-	formToggleOnStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("82")).
-				Bold(true)
+// applyTheme points every rendering style var at the styles from t. Takes
+// effect on the next View() render.
+func applyTheme(t Theme) {
+	titleStyle = t.Title
+	headerStyle = t.Header
+	selectedStyle = t.Selected
+	categoryStyle = t.Category
+	dimStyle = t.Dim
+	helpStyle = t.Help
+	statusStyle = t.Status
+	errorStyle = t.Error
+	cursorStyle = t.Cursor
+	formLabelStyle = t.FormLabel
+	formInputStyle = t.FormInput
+	formActiveStyle = t.FormActive
+	formToggleOnStyle = t.FormToggleOn
+	formToggleOffStyle = t.FormToggleOff
+	matchStyle = t.Match
+}
 
-	formToggleOffStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("241"))
-)
+// AdvancedSearch holds the structured search criteria driven by the
+// advanced search form, and, via the compact query DSL (query.go), the
+// normal search bar too.
+type AdvancedSearch struct {
+	Title    string
+	Group    string
+	Language string
+	Region   string
+	Engine   string
+	FileType string
+
+	MinTrainers int
+	MaxTrainers int // -1 means no upper bound.
+
+	Top200Only  bool
+	Is4kOnly    bool
+	HasDocs     bool
+	HasFastload bool
+	IsCracked   *bool // nil = any, else require true/false.
+}
 
 // Model represents the TUI application state.
 type Model struct {
@@ -107,20 +123,117 @@ type Model struct {
 	width            int
 	height           int
 	statusMessage    string
+	statusIsError    bool // Renders statusMessage in errorStyle, e.g. for query DSL parse errors.
 	err              error
 	quitting         bool
 	assembly64Path   string
 	legacyMode       bool // True if using legacy .releaselog.json loading (enables refresh)
 
+	// Fuzzy search state. fuzzyMode toggles between substring containment
+	// (the default) and matcher-scored fuzzy matching; filteredScores and
+	// filteredPositions run parallel to filteredResults and are only
+	// populated (non-zero/non-nil) in fuzzy mode, for ranking and
+	// highlighting matched runes respectively.
+	fuzzyMode         bool
+	matcher           Matcher
+	filteredScores    []int
+	filteredPositions [][]int
+
 	// Advanced search state.
 	mode           searchMode
 	advSearch      AdvancedSearch
 	activeField    advancedField
 	advFieldValues [fieldCount]string // Text values for text input fields.
+
+	// parsedQuery holds the result of parsing the normal search bar as the
+	// compact query DSL (query.go). Non-nil only when searchQuery contains
+	// a structured field:value predicate; nil while driven by the
+	// advanced search form.
+	parsedQuery *ParsedQuery
+
+	// Preview pane state. showPreview toggles the preview pane (split
+	// view on a wide terminal, stacked below the results on a narrow
+	// one - see View); previewScroll is the first visible line of the
+	// preview content for the entry under the cursor, reset whenever the
+	// cursor moves. previewCache holds buildPreviewLines' output keyed by
+	// FullPath so scrolling the results list doesn't re-read and
+	// re-parse the underlying file on every cursor move.
+	showPreview   bool
+	previewScroll int
+	previewCache  map[string][]string
+
+	// Disk browser state, opened with space over a disk-image entry (see
+	// openDiskBrowser/handleDiskBrowserKeyMsg). diskBrowserEntry is the
+	// entry being browsed; diskBrowserDirEntries is its decoded
+	// directory listing; diskBrowserDiskData is the (GCR-decoded, for
+	// G64/G71) bytes PRGs are extracted from on Enter; diskBrowserCursor
+	// is the highlighted row.
+	diskBrowserEntry      ReleaseEntry
+	diskBrowserDirEntries []directoryEntry
+	diskBrowserDiskData   []byte
+	diskBrowserCursor     int
+
+	// Theme state (theme.go). themes is every installed theme, built-ins
+	// plus anything found on disk; themeIndex is the active one. Pressing
+	// T cycles themeIndex and re-applies it via applyTheme.
+	themes     []Theme
+	themeIndex int
+
+	// History/saved-query state (history.go). history and savedQueries
+	// are loaded lazily - see loadHistory/loadSavedQueries - so startup
+	// latency is unaffected when neither file exists yet. pickerQuery and
+	// pickerCursor are scratch state shared by whichever picker mode
+	// (modeHistoryPicker/modeSavedQueryPicker) is active; saveQueryName
+	// is the text typed into the Ctrl+S name prompt.
+	historyStore       HistoryStore
+	history            []string
+	historyLoaded      bool
+	savedQueries       []SavedQuery
+	savedQueriesLoaded bool
+	pickerQuery        string
+	pickerCursor       int
+	saveQueryName      string
+
+	// Sort state (sort.go), persisted to sort.json next to the JSON
+	// database (dbPath) so the user's chosen view survives restarts.
+	// sortField selects the column filteredResults are ordered by
+	// outside fuzzy mode (fuzzy mode ranks by match relevance instead);
+	// sortDescending reverses it. s cycles sortField, r flips
+	// sortDescending.
+	sortField      sortField
+	sortDescending bool
+	dbPath         string
+
+	// Background load queue (this file, loadJob/enqueueSelectedEntry/Enter and m
+	// below). Enter queues a normal load; m queues a "load without run"
+	// (mount disk, don't call runPRG) so several disks can be prepared
+	// in /Temp and swapped between. jobUpdates is how the worker
+	// goroutines report progress back into Update - see waitForJobUpdate,
+	// which Init starts listening on and every jobUpdateMsg re-arms.
+	jobs       []*loadJob
+	nextJobID  int
+	jobUpdates chan jobUpdateMsg
+
+	// downloadCache serves (and persists to ~/.cache/c64uploader) the
+	// content of entries whose FullPath is a remote URL, so runLoadJob
+	// doesn't re-download the same release on every load. Nil disables
+	// caching - entries are fetched straight into memory via readFile,
+	// same as before this existed.
+	downloadCache *downloadCache
 }
 
-// NewModel creates a new TUI model.
-func NewModel(index *SearchIndex, apiClient *APIClient, assembly64Path string, legacyMode bool) Model {
+// NewModel creates a new TUI model. themeName selects the initial theme
+// by name (case-insensitive); an unknown or empty name falls back to the
+// first installed theme ("default"). dbPath is the JSON database path
+// (even in legacy mode, where it just may not exist); it locates
+// sort.json, the persisted sort field/order.
+func NewModel(index *SearchIndex, apiClient *APIClient, assembly64Path string, legacyMode bool, themeName string, dbPath string, downloadCache *downloadCache) Model {
+	themes := loadThemes()
+	themeIndex := findTheme(themes, themeName)
+	applyTheme(themes[themeIndex])
+
+	sortFieldVal, sortDescendingVal := loadSortState(dbPath)
+
 	m := Model{
 		index:            index,
 		apiClient:        apiClient,
@@ -129,20 +242,78 @@ func NewModel(index *SearchIndex, apiClient *APIClient, assembly64Path string, l
 		selectedCategory: "All",
 		searchQuery:      "",
 		filteredResults:  make([]int, 0),
+		matcher:          fuzzyMatcher{},
 		mode:             modeNormal,
 		advSearch:        AdvancedSearch{MaxTrainers: -1},
+		themes:           themes,
+		themeIndex:       themeIndex,
+		historyStore:     fileHistoryStore{},
+		sortField:        sortFieldVal,
+		sortDescending:   sortDescendingVal,
+		dbPath:           dbPath,
+		previewCache:     make(map[string][]string),
+		jobUpdates:       make(chan jobUpdateMsg, 16),
+		downloadCache:    downloadCache,
 	}
 	m.applyFilters()
 	return m
 }
 
-// Init initializes the model.
+// cycleTheme advances to the next installed theme and applies it; takes
+// effect on the next View() render.
+func (m *Model) cycleTheme() {
+	m.themeIndex = (m.themeIndex + 1) % len(m.themes)
+	applyTheme(m.themes[m.themeIndex])
+}
+
+// persistSortState saves the current sort field/order to sort.json and
+// surfaces a status message - including the current sort, since this is
+// the only feedback the s/r keys otherwise give - or a write error.
+func (m *Model) persistSortState() {
+	if err := saveSortState(m.dbPath, m.sortField, m.sortDescending); err != nil {
+		m.statusMessage = fmt.Sprintf("failed to save sort state: %v", err)
+		m.statusIsError = true
+		return
+	}
+	m.statusIsError = false
+	m.statusMessage = fmt.Sprintf("Sort: %s %s", m.sortField, sortArrow(m.sortDescending))
+}
+
+// loadHistory lazily loads recent search strings from m.historyStore on
+// first call; later calls are a no-op. A load error is swallowed - it
+// just means Ctrl+R opens an empty picker rather than failing startup.
+func (m *Model) loadHistory() {
+	if m.historyLoaded {
+		return
+	}
+	m.historyLoaded = true
+	if history, err := m.historyStore.LoadHistory(); err == nil {
+		m.history = history
+	}
+}
+
+// loadSavedQueries lazily loads saved named queries from m.historyStore
+// on first call; later calls are a no-op. A load error is swallowed - it
+// just means Ctrl+O opens an empty picker rather than failing startup.
+func (m *Model) loadSavedQueries() {
+	if m.savedQueriesLoaded {
+		return
+	}
+	m.savedQueriesLoaded = true
+	if saved, err := m.historyStore.LoadSavedQueries(); err == nil {
+		m.savedQueries = saved
+	}
+}
+
+// Init initializes the model, starting the listener that picks up
+// background load jobs' progress (see waitForJobUpdate).
 func (m Model) Init() tea.Cmd {
-	return nil
+	return m.waitForJobUpdate()
 }
 
 // handleNavigation handles cursor navigation keys.
 func (m *Model) handleNavigation(key string) {
+	m.previewScroll = 0
 	switch key {
 	case "up":
 		if m.cursor > 0 {
@@ -172,8 +343,17 @@ func (m *Model) handleNavigation(key string) {
 // handleKeyMsg processes keyboard messages.
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Handle mode-specific keys.
-	if m.mode == modeAdvanced {
+	switch m.mode {
+	case modeAdvanced:
 		return m.handleAdvancedKeyMsg(msg)
+	case modeHistoryPicker:
+		return m.handleHistoryPickerKeyMsg(msg)
+	case modeSavedQueryPicker:
+		return m.handleSavedQueryPickerKeyMsg(msg)
+	case modeSaveQueryPrompt:
+		return m.handleSaveQueryPromptKeyMsg(msg)
+	case modeDiskBrowser:
+		return m.handleDiskBrowserKeyMsg(msg)
 	}
 	return m.handleNormalKeyMsg(msg)
 }
@@ -207,6 +387,19 @@ func (m Model) handleNormalKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "ctrl+f":
+		m.fuzzyMode = !m.fuzzyMode
+		m.cursor = 0
+		m.scrollOffset = 0
+		m.applyFilters()
+		m.statusIsError = false
+		if m.fuzzyMode {
+			m.statusMessage = "Fuzzy search mode"
+		} else {
+			m.statusMessage = "Substring search mode"
+		}
+		return m, nil
+
 	case "ctrl+l":
 		if m.legacyMode {
 			// In legacy mode, reload the index from disk.
@@ -216,12 +409,28 @@ func (m Model) handleNormalKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.searchQuery = ""
 		m.advSearch = AdvancedSearch{MaxTrainers: -1}
 		m.advFieldValues = [fieldCount]string{}
+		m.parsedQuery = nil
 		m.cursor = 0
 		m.scrollOffset = 0
 		m.applyFilters()
+		m.statusIsError = false
 		m.statusMessage = "Search reset"
 		return m, nil
 
+	case "ctrl+r":
+		m.loadHistory()
+		m.mode = modeHistoryPicker
+		m.pickerQuery = ""
+		m.pickerCursor = 0
+		return m, nil
+
+	case "ctrl+o":
+		m.loadSavedQueries()
+		m.mode = modeSavedQueryPicker
+		m.pickerQuery = ""
+		m.pickerCursor = 0
+		return m, nil
+
 	case "tab":
 		// Cycle through categories.
 		currentIdx := -1
@@ -242,8 +451,63 @@ func (m Model) handleNormalKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.handleNavigation(msg.String())
 		return m, nil
 
+	case "p":
+		m.showPreview = !m.showPreview
+		m.previewScroll = 0
+		return m, nil
+
+	case " ":
+		return m.openDiskBrowser()
+
+	case "T":
+		m.cycleTheme()
+		m.statusIsError = false
+		m.statusMessage = fmt.Sprintf("Theme: %s", m.themes[m.themeIndex].Name)
+		return m, nil
+
+	case "s":
+		m.sortField = (m.sortField + 1) % sortFieldCount
+		m.applyFilters()
+		m.persistSortState()
+		return m, nil
+
+	case "r":
+		m.sortDescending = !m.sortDescending
+		m.applyFilters()
+		m.persistSortState()
+		return m, nil
+
+	case "shift+up":
+		if m.previewScroll > 0 {
+			m.previewScroll--
+		}
+		return m, nil
+
+	case "shift+down":
+		m.previewScroll++
+		return m, nil
+
 	case "enter":
-		return m, m.loadSelectedEntry()
+		if m.searchQuery != "" {
+			m.loadHistory() // Load first so we append to, not replace, history saved in another run.
+			m.history = addHistoryEntry(m.history, m.searchQuery)
+			if err := m.historyStore.SaveHistory(m.history); err != nil {
+				m.statusMessage = fmt.Sprintf("failed to save search history: %v", err)
+				m.statusIsError = true
+			}
+		}
+		return m, m.enqueueSelectedEntry(true)
+
+	case "m":
+		return m, m.enqueueSelectedEntry(false)
+
+	case "d":
+		m.cancelLastPendingJob()
+		return m, nil
+
+	case "D":
+		m.clearFinishedJobs()
+		return m, nil
 
 	case "backspace":
 		if len(m.searchQuery) > 0 {
@@ -278,6 +542,12 @@ func (m Model) handleAdvancedKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.mode = modeNormal
 		return m, nil
 
+	case "ctrl+s":
+		m.loadSavedQueries()
+		m.mode = modeSaveQueryPrompt
+		m.saveQueryName = ""
+		return m, nil
+
 	case "enter":
 		// Apply advanced search and return to results.
 		m.applyAdvancedSearch()
@@ -335,6 +605,344 @@ func (m Model) handleAdvancedKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
 	}
 }
 
+// handleHistoryPickerKeyMsg handles keys while picking a prior search
+// string, opened with Ctrl+R (see handleNormalKeyMsg). Typing narrows
+// the list via m.matcher; Enter loads the selected string into the
+// search bar and re-applies filters.
+func (m Model) handleHistoryPickerKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
+	indices := filterPickerItems(m.matcher, m.pickerQuery, m.history)
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "esc":
+		m.mode = modeNormal
+		return m, nil
+
+	case "enter":
+		if m.pickerCursor < len(indices) {
+			m.searchQuery = m.history[indices[m.pickerCursor]]
+			m.cursor = 0
+			m.scrollOffset = 0
+			m.applyFilters()
+		}
+		m.mode = modeNormal
+		return m, nil
+
+	case "up":
+		if m.pickerCursor > 0 {
+			m.pickerCursor--
+		}
+		return m, nil
+
+	case "down":
+		if m.pickerCursor < len(indices)-1 {
+			m.pickerCursor++
+		}
+		return m, nil
+
+	case "backspace":
+		if len(m.pickerQuery) > 0 {
+			m.pickerQuery = m.pickerQuery[:len(m.pickerQuery)-1]
+			m.pickerCursor = 0
+		}
+		return m, nil
+
+	default:
+		if len(msg.String()) == 1 && msg.String()[0] >= 32 && msg.String()[0] <= 126 {
+			m.pickerQuery += msg.String()
+			m.pickerCursor = 0
+		}
+		return m, nil
+	}
+}
+
+// handleSavedQueryPickerKeyMsg handles keys while picking a saved named
+// query, opened with Ctrl+O (see handleNormalKeyMsg). Enter restores the
+// selected query's AdvancedSearch and category into the model and
+// re-applies filters.
+func (m Model) handleSavedQueryPickerKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
+	names := savedQueryNames(m.savedQueries)
+	indices := filterPickerItems(m.matcher, m.pickerQuery, names)
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "esc":
+		m.mode = modeNormal
+		return m, nil
+
+	case "enter":
+		if m.pickerCursor < len(indices) {
+			sq := m.savedQueries[indices[m.pickerCursor]]
+			m.advSearch = sq.Search
+			m.advFieldValues = advancedSearchToFieldValues(sq.Search)
+			if sq.Category != "" {
+				m.selectedCategory = sq.Category
+			}
+			m.parsedQuery = nil
+			m.cursor = 0
+			m.scrollOffset = 0
+			m.applyAdvancedFilters()
+		}
+		m.mode = modeNormal
+		return m, nil
+
+	case "up":
+		if m.pickerCursor > 0 {
+			m.pickerCursor--
+		}
+		return m, nil
+
+	case "down":
+		if m.pickerCursor < len(indices)-1 {
+			m.pickerCursor++
+		}
+		return m, nil
+
+	case "backspace":
+		if len(m.pickerQuery) > 0 {
+			m.pickerQuery = m.pickerQuery[:len(m.pickerQuery)-1]
+			m.pickerCursor = 0
+		}
+		return m, nil
+
+	default:
+		if len(msg.String()) == 1 && msg.String()[0] >= 32 && msg.String()[0] <= 126 {
+			m.pickerQuery += msg.String()
+			m.pickerCursor = 0
+		}
+		return m, nil
+	}
+}
+
+// handleSaveQueryPromptKeyMsg handles keys while typing a name for the
+// Ctrl+S prompt (see handleAdvancedKeyMsg). Enter serializes the current
+// AdvancedSearch and selectedCategory to queries.json under that name,
+// replacing any existing saved query with the same name.
+func (m Model) handleSaveQueryPromptKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "esc":
+		m.mode = modeAdvanced
+		return m, nil
+
+	case "enter":
+		name := strings.TrimSpace(m.saveQueryName)
+		if name == "" {
+			m.mode = modeAdvanced
+			return m, nil
+		}
+
+		sq := SavedQuery{Name: name, Category: m.selectedCategory, Search: m.advSearch}
+		replaced := false
+		for i, existing := range m.savedQueries {
+			if existing.Name == name {
+				m.savedQueries[i] = sq
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			m.savedQueries = append(m.savedQueries, sq)
+		}
+
+		if err := m.historyStore.SaveSavedQueries(m.savedQueries); err != nil {
+			m.statusMessage = fmt.Sprintf("failed to save query: %v", err)
+			m.statusIsError = true
+		} else {
+			m.statusMessage = fmt.Sprintf("Saved query %q", name)
+			m.statusIsError = false
+		}
+		m.mode = modeAdvanced
+		return m, nil
+
+	case "backspace":
+		if len(m.saveQueryName) > 0 {
+			m.saveQueryName = m.saveQueryName[:len(m.saveQueryName)-1]
+		}
+		return m, nil
+
+	default:
+		if len(msg.String()) == 1 && msg.String()[0] >= 32 && msg.String()[0] <= 126 {
+			m.saveQueryName += msg.String()
+		}
+		return m, nil
+	}
+}
+
+// savedQueryNames extracts the Name of every saved query, in display
+// (i.e. saved) order.
+func savedQueryNames(queries []SavedQuery) []string {
+	names := make([]string, len(queries))
+	for i, q := range queries {
+		names[i] = q.Name
+	}
+	return names
+}
+
+// advancedSearchToFieldValues renders an AdvancedSearch back into the
+// advanced search form's text field values, the inverse of the text
+// field assignments in applyAdvancedSearch. Toggle fields live directly
+// on AdvancedSearch and need no form value.
+func advancedSearchToFieldValues(as AdvancedSearch) [fieldCount]string {
+	var values [fieldCount]string
+	values[fieldTitle] = as.Title
+	values[fieldGroup] = as.Group
+	values[fieldLanguage] = as.Language
+	values[fieldRegion] = as.Region
+	values[fieldEngine] = as.Engine
+	values[fieldFileType] = as.FileType
+	if as.MinTrainers != 0 {
+		values[fieldMinTrainers] = strconv.Itoa(as.MinTrainers)
+	}
+	if as.MaxTrainers != -1 {
+		values[fieldMaxTrainers] = strconv.Itoa(as.MaxTrainers)
+	}
+	return values
+}
+
+// openDiskBrowser enters the disk contents modal (space; see
+// handleNormalKeyMsg) for the entry under the cursor, reading and
+// parsing its directory so the user can pick a specific PRG to run
+// instead of runDiskImage's auto-run-the-first-PRG default. A non-disk
+// entry, or a read/parse failure, surfaces as a status message instead
+// of opening the modal.
+func (m Model) openDiskBrowser() (Model, tea.Cmd) {
+	if len(m.filteredResults) == 0 || m.cursor >= len(m.filteredResults) {
+		return m, nil
+	}
+	entry := m.index.Entries[m.filteredResults[m.cursor]]
+
+	switch entry.FileType {
+	case "d64", "d71", "d81", "g64", "g71":
+	default:
+		m.statusMessage = "Disk contents browser is only available for disk images"
+		m.statusIsError = true
+		return m, nil
+	}
+
+	data, err := readFile(entry.FullPath)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("failed to read disk image: %v", err)
+		m.statusIsError = true
+		return m, nil
+	}
+
+	diskData := data
+	if entry.FileType == "g64" || entry.FileType == "g71" {
+		decoded, err := decodeG64ToD64(data)
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("could not decode GCR image: %v", err)
+			m.statusIsError = true
+			return m, nil
+		}
+		diskData = decoded
+	}
+
+	dirEntries, err := listDirectory(diskData)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("could not read directory: %v", err)
+		m.statusIsError = true
+		return m, nil
+	}
+
+	m.diskBrowserEntry = entry
+	m.diskBrowserDiskData = diskData
+	m.diskBrowserDirEntries = dirEntries
+	m.diskBrowserCursor = 0
+	m.mode = modeDiskBrowser
+	return m, nil
+}
+
+// handleDiskBrowserKeyMsg handles keys in the disk contents modal
+// (opened with space; see openDiskBrowser).
+func (m Model) handleDiskBrowserKeyMsg(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+
+	case "esc", " ":
+		m.mode = modeNormal
+		return m, nil
+
+	case "up":
+		if m.diskBrowserCursor > 0 {
+			m.diskBrowserCursor--
+		}
+		return m, nil
+
+	case "down":
+		if m.diskBrowserCursor < len(m.diskBrowserDirEntries)-1 {
+			m.diskBrowserCursor++
+		}
+		return m, nil
+
+	case "enter":
+		m.mode = modeNormal
+		return m, m.runSelectedDiskEntry()
+	}
+	return m, nil
+}
+
+// runSelectedDiskEntry mounts the browsed disk image and runs the
+// highlighted PRG - the disk-browser equivalent of enqueueSelectedEntry,
+// but lets the user pick any PRG on the disk rather than always the
+// first one. It runs synchronously rather than as a queued background
+// job since it's already a one-off action inside a modal dialog.
+func (m *Model) runSelectedDiskEntry() tea.Cmd {
+	entries := m.diskBrowserDirEntries
+	cursor := m.diskBrowserCursor
+	diskData := m.diskBrowserDiskData
+	fileType := m.diskBrowserEntry.FileType
+	filename := filepath.Base(m.diskBrowserEntry.FullPath)
+	apiClient := m.apiClient
+
+	return func() tea.Msg {
+		if cursor >= len(entries) {
+			return statusMsg{err: fmt.Errorf("no file selected")}
+		}
+		selected := entries[cursor]
+		if selected.fileType != fileTypePRG {
+			return statusMsg{err: fmt.Errorf("%s is not a PRG", selected.filename)}
+		}
+
+		prgData, err := extractFileData(diskData, int(selected.track), int(selected.sector))
+		if err != nil {
+			return statusMsg{err: fmt.Errorf("failed to extract %s: %w", selected.filename, err)}
+		}
+
+		ctx := context.Background()
+
+		// Remove previously mounted disk to free up space; best-effort,
+		// since there may be nothing mounted yet.
+		if err := apiClient.removeDisk(ctx); err != nil {
+			slog.Debug("Failed to remove previous disk (may not be mounted)", "error", err)
+		}
+
+		remotePath, err := apiClient.uploadDiskViaFTPDeduped(ctx, diskData, filename, nil)
+		if err != nil {
+			return statusMsg{err: fmt.Errorf("failed to upload disk: %w", err)}
+		}
+		if err := apiClient.mountDisk(ctx, remotePath, fileType); err != nil {
+			return statusMsg{err: fmt.Errorf("failed to mount disk: %w", err)}
+		}
+		if err := apiClient.runPRG(ctx, prgData, nil); err != nil {
+			return statusMsg{err: fmt.Errorf("failed to run %s: %w", selected.filename, err)}
+		}
+
+		return statusMsg{message: fmt.Sprintf("✓ Loaded: %s", selected.filename)}
+	}
+}
+
 // isTextField returns true if the field accepts text input.
 func (m *Model) isTextField(field advancedField) bool {
 	switch field {
@@ -419,6 +1027,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMessage = ""
 		} else {
 			m.statusMessage = msg.message
+			m.statusIsError = false
 			m.err = nil
 		}
 		return m, nil
@@ -431,6 +1040,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Replace the index with the refreshed one.
 			m.index = msg.index
 			m.statusMessage = "✓ Index refreshed"
+			m.statusIsError = false
 			m.err = nil
 			// Re-apply filters to update the display.
 			m.cursor = 0
@@ -439,6 +1049,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case jobUpdateMsg:
+		m.applyJobUpdate(msg)
+		return m, m.waitForJobUpdate()
+
 	case tea.KeyMsg:
 		return m.handleKeyMsg(msg)
 	}
@@ -476,6 +1090,11 @@ func (m Model) renderHeader() string {
 		b.WriteString(m.searchQuery)
 		b.WriteString(cursorStyle.Render(" "))
 	}
+	b.WriteString("\n")
+
+	// Sort indicator. Only meaningful outside fuzzy mode, which ranks by
+	// match relevance instead (see sortCandidates).
+	b.WriteString(dimStyle.Render(fmt.Sprintf("Sort: %s %s", m.sortField, sortArrow(m.sortDescending))))
 	b.WriteString("\n\n")
 
 	return b.String()
@@ -495,21 +1114,67 @@ func (m Model) renderResults(viewHeight int) string {
 
 		for i := start; i < end; i++ {
 			entry := m.index.Entries[m.filteredResults[i]]
-			line := m.formatEntry(entry, i == m.cursor)
+			var positions []int
+			if i < len(m.filteredPositions) {
+				positions = m.filteredPositions[i]
+			}
+			line := m.formatEntry(entry, i == m.cursor, positions)
 			b.WriteString(line)
 			b.WriteString("\n")
 		}
 
-		// Result count.
+		// Result count, with a reminder of what order they're in.
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render(fmt.Sprintf("[%d results]", len(m.filteredResults))))
+		b.WriteString(dimStyle.Render(fmt.Sprintf("[%d results, sorted by %s %s]", len(m.filteredResults), m.sortField, sortArrow(m.sortDescending))))
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-// renderFooter renders status messages and help text.
+// renderJobs renders the inline load-job queue: one line per queued,
+// in-flight, or recently finished load, with a percentage while
+// downloading or uploading. Finished jobs stay listed - so a failure is
+// visible - until cleared with D.
+func (m Model) renderJobs() string {
+	if len(m.jobs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, j := range m.jobs {
+		var status string
+		switch j.state {
+		case "downloading":
+			status = fmt.Sprintf("downloading %d%%", j.progress)
+		case "uploading":
+			status = fmt.Sprintf("uploading %d%%", j.progress)
+		case "done":
+			status = "✓ done"
+		case "failed":
+			status = fmt.Sprintf("✗ failed: %v", j.err)
+		case "canceled":
+			status = "canceled"
+		default:
+			status = j.state
+		}
+
+		mode := ""
+		if !j.runAfter {
+			mode = " (mount only)"
+		}
+
+		style := dimStyle
+		if j.state == "failed" {
+			style = errorStyle
+		}
+		b.WriteString(style.Render(fmt.Sprintf("[%d] %s%s: %s", j.id, j.entry.Name, mode, status)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderFooter renders status messages, the load queue, and help text.
 func (m Model) renderFooter() string {
 	var b strings.Builder
 
@@ -518,16 +1183,29 @@ func (m Model) renderFooter() string {
 		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
 		b.WriteString("\n")
 	} else if m.statusMessage != "" {
-		b.WriteString(statusStyle.Render(m.statusMessage))
+		style := statusStyle
+		if m.statusIsError {
+			style = errorStyle
+		}
+		b.WriteString(style.Render(m.statusMessage))
 		b.WriteString("\n")
 	}
 
+	// Background load queue, if anything's queued/running/finished.
+	b.WriteString(m.renderJobs())
+
 	// Help text.
 	var helpText string
 	if m.legacyMode {
-		helpText = "↑/↓: Navigate  Tab: Category  Enter: Load  Ctrl+L: Refresh  Esc/Q: Quit"
+		helpText = "↑/↓: Navigate  Tab: Category  Enter: Load  m: Load (no run)  d: Cancel  D: Clear  Space: Disk contents  Ctrl+F: Fuzzy  Ctrl+L: Refresh  Ctrl+R: History  Ctrl+O: Saved  S: Sort  R: Reverse  P: Preview  T: Theme  Esc/Q: Quit"
 	} else {
-		helpText = "↑/↓: Navigate  Tab: Category  /: Advanced  Enter: Load  Ctrl+L: Reset  Esc/Q: Quit"
+		helpText = "↑/↓: Navigate  Tab: Category  /: Advanced  Enter: Load  m: Load (no run)  d: Cancel  D: Clear  Space: Disk contents  Ctrl+F: Fuzzy  Ctrl+L: Reset  Ctrl+R: History  Ctrl+O: Saved  S: Sort  R: Reverse  P: Preview  T: Theme  Esc/Q: Quit"
+	}
+	if m.fuzzyMode {
+		helpText += "  [FUZZY]"
+	}
+	if m.showPreview {
+		helpText += "  Shift+↑/↓: Scroll preview"
 	}
 	b.WriteString(helpStyle.Render(helpText))
 	b.WriteString("\n")
@@ -554,9 +1232,18 @@ func (m Model) View() string {
 		return ""
 	}
 
-	// Render advanced search form if in that mode.
-	if m.mode == modeAdvanced {
+	// Render mode-specific full-screen views.
+	switch m.mode {
+	case modeAdvanced:
 		return m.renderAdvancedSearchForm()
+	case modeHistoryPicker:
+		return m.renderHistoryPicker()
+	case modeSavedQueryPicker:
+		return m.renderSavedQueryPicker()
+	case modeSaveQueryPrompt:
+		return m.renderSaveQueryPrompt()
+	case modeDiskBrowser:
+		return m.renderDiskBrowser()
 	}
 
 	var b strings.Builder
@@ -570,8 +1257,38 @@ func (m Model) View() string {
 		viewHeight = 5
 	}
 
-	// Render results.
-	b.WriteString(m.renderResults(viewHeight))
+	// Render results, with the preview pane alongside if toggled on: a
+	// right-hand split when the terminal is wide enough, else stacked
+	// below the results so the pane survives on narrower terminals too.
+	switch {
+	case m.showPreview && m.width >= minPreviewWidth:
+		resultsWidth := m.width * 3 / 5
+		previewWidth := m.width - resultsWidth - 1
+
+		results := lipgloss.NewStyle().Width(resultsWidth).Render(m.renderResults(viewHeight))
+		preview := lipgloss.NewStyle().Width(previewWidth).Render(m.renderPreview(viewHeight))
+
+		dividerLines := make([]string, viewHeight)
+		for i := range dividerLines {
+			dividerLines[i] = "│"
+		}
+		divider := dimStyle.Render(strings.Join(dividerLines, "\n"))
+
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, results, divider, preview))
+		b.WriteString("\n")
+
+	case m.showPreview:
+		listHeight := viewHeight / 2
+		previewHeight := viewHeight - listHeight
+
+		b.WriteString(m.renderResults(listHeight))
+		b.WriteString(dimStyle.Render(strings.Repeat("─", max(m.width, 1))))
+		b.WriteString("\n")
+		b.WriteString(m.renderPreview(previewHeight))
+
+	default:
+		b.WriteString(m.renderResults(viewHeight))
+	}
 
 	// Render footer.
 	b.WriteString(m.renderFooter())
@@ -579,6 +1296,159 @@ func (m Model) View() string {
 	return b.String()
 }
 
+// minPreviewWidth is the narrowest terminal the split-view preview pane
+// will render in; below it, View stacks the pane below the results
+// instead of placing it in a right-hand column.
+const minPreviewWidth = 60
+
+// renderPreview renders rich metadata and container-specific detail for
+// the entry under the cursor (split-view or stacked - see View),
+// scrollable independently of the results list via shift+up/down.
+// buildPreviewLines' output is cached in m.previewCache keyed by
+// FullPath, so scrolling the results list doesn't re-read and re-parse
+// the underlying file on every cursor move.
+func (m Model) renderPreview(viewHeight int) string {
+	if len(m.filteredResults) == 0 || m.cursor >= len(m.filteredResults) {
+		return dimStyle.Render("No entry selected")
+	}
+
+	entry := m.index.Entries[m.filteredResults[m.cursor]]
+	lines, ok := m.previewCache[entry.FullPath]
+	if !ok {
+		lines = buildPreviewLines(entry)
+		m.previewCache[entry.FullPath] = lines
+	}
+
+	start := m.previewScroll
+	if start > len(lines) {
+		start = len(lines)
+	}
+	end := min(start+viewHeight, len(lines))
+
+	var b strings.Builder
+	for _, line := range lines[start:end] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// buildPreviewLines composes the preview pane's content for entry: core
+// metadata common to every file type, crack info when present, and a
+// "Contents" section with container-specific detail (see
+// buildContainerPreviewLines).
+func buildPreviewLines(entry ReleaseEntry) []string {
+	var lines []string
+
+	lines = append(lines, headerStyle.Render(entry.Name))
+	if entry.Group != "" {
+		lines = append(lines, fmt.Sprintf("Group:    %s", entry.Group))
+	}
+	if entry.Year != "" {
+		lines = append(lines, fmt.Sprintf("Year:     %s", entry.Year))
+	}
+	lines = append(lines, fmt.Sprintf("Category: %s", entry.CategoryName))
+	if entry.Language != "" {
+		lines = append(lines, fmt.Sprintf("Language: %s", entry.Language))
+	}
+	if entry.Region != "" {
+		lines = append(lines, fmt.Sprintf("Region:   %s", entry.Region))
+	}
+	if entry.Engine != "" {
+		lines = append(lines, fmt.Sprintf("Engine:   %s", entry.Engine))
+	}
+	if entry.Top200Rank > 0 {
+		lines = append(lines, fmt.Sprintf("Top 200:  #%d", entry.Top200Rank))
+	}
+
+	if entry.Crack != nil {
+		lines = append(lines, "", headerStyle.Render("Crack info"))
+		lines = append(lines, fmt.Sprintf("Cracked:  %v", entry.Crack.IsCracked))
+		lines = append(lines, fmt.Sprintf("Trainers: %d", entry.Crack.Trainers))
+		if len(entry.Crack.Flags) > 0 {
+			lines = append(lines, fmt.Sprintf("Flags:    %s", strings.Join(entry.Crack.Flags, ", ")))
+		}
+	}
+
+	lines = append(lines, fmt.Sprintf("Type:     .%s", entry.FileType))
+	if info, err := os.Stat(entry.FullPath); err == nil {
+		lines = append(lines, fmt.Sprintf("Size:     %d bytes", info.Size()))
+	}
+	lines = append(lines, fmt.Sprintf("Path:     %s", entry.FullPath))
+	if entry.Hash != "" {
+		lines = append(lines, fmt.Sprintf("SHA-256:  %s", entry.Hash))
+	}
+
+	lines = append(lines, "", headerStyle.Render("Contents"))
+	lines = append(lines, buildContainerPreviewLines(entry)...)
+
+	return lines
+}
+
+// buildContainerPreviewLines reads entry.FullPath and renders
+// container-specific detail: a directory listing with block counts for
+// D64/D71/D81/G64/G71 disk images, the load address and length for a
+// bare PRG, or the cartridge hardware type and name for a CRT. Read or
+// parse failures surface as a single dimmed line rather than failing the
+// whole preview.
+func buildContainerPreviewLines(entry ReleaseEntry) []string {
+	data, err := readFile(entry.FullPath)
+	if err != nil {
+		return []string{dimStyle.Render(fmt.Sprintf("(unreadable: %v)", err))}
+	}
+
+	switch entry.FileType {
+	case "d64", "d71", "d81", "g64", "g71":
+		diskData := data
+		if entry.FileType == "g64" || entry.FileType == "g71" {
+			decoded, err := decodeG64ToD64(data)
+			if err != nil {
+				return []string{dimStyle.Render(fmt.Sprintf("(could not decode GCR image: %v)", err))}
+			}
+			diskData = decoded
+		}
+
+		var dirEntries []directoryEntry
+		if entry.FileType == "d81" {
+			dirEntries, err = listD81Directory(diskData)
+		} else {
+			dirEntries, err = listDirectory(diskData)
+		}
+		if err != nil {
+			return []string{dimStyle.Render(fmt.Sprintf("(could not read directory: %v)", err))}
+		}
+		lines := make([]string, 0, len(dirEntries))
+		for _, e := range dirEntries {
+			lines = append(lines, fmt.Sprintf("%-16s %-3s %3d blk", e.filename, d64FileTypeName(e.fileType), e.blockCount))
+		}
+		return lines
+
+	case "prg":
+		if len(data) < 2 {
+			return []string{dimStyle.Render("(PRG too small to have a load address)")}
+		}
+		loadAddr := uint16(data[0]) | uint16(data[1])<<8
+		return []string{
+			fmt.Sprintf("Load address: $%04X", loadAddr),
+			fmt.Sprintf("Length:       %d bytes", len(data)-2),
+		}
+
+	case "crt":
+		header, err := parseCRTHeader(data)
+		if err != nil {
+			return []string{dimStyle.Render(fmt.Sprintf("(could not read CRT header: %v)", err))}
+		}
+		lines := []string{fmt.Sprintf("Hardware type: %d", header.hardwareType)}
+		if header.name != "" {
+			lines = append(lines, fmt.Sprintf("Cartridge name: %s", header.name))
+		}
+		return lines
+
+	default:
+		return []string{dimStyle.Render("(no preview available for this file type)")}
+	}
+}
+
 // renderAdvancedSearchForm renders the advanced search form.
 func (m Model) renderAdvancedSearchForm() string {
 	var b strings.Builder
@@ -636,7 +1506,107 @@ func (m Model) renderAdvancedSearchForm() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓/Tab: Navigate  Space: Toggle  Enter: Search  Esc: Cancel"))
+	b.WriteString(helpStyle.Render("↑/↓/Tab: Navigate  Space: Toggle  Enter: Search  Ctrl+S: Save Query  Esc: Cancel"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderPickerList renders a simple filtered, cursor-navigable list, the
+// common shape shared by the history and saved-query pickers: a title, a
+// filter line, and the matching items with the current selection
+// highlighted.
+func renderPickerList(title, query string, items []string, indices []int, cursor int) string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	b.WriteString(headerStyle.Render("Filter: "))
+	if query == "" {
+		b.WriteString(dimStyle.Render("(type to filter)"))
+	} else {
+		b.WriteString(query)
+		b.WriteString(cursorStyle.Render(" "))
+	}
+	b.WriteString("\n\n")
+
+	if len(indices) == 0 {
+		b.WriteString(dimStyle.Render("(nothing here yet)"))
+		b.WriteString("\n")
+	}
+	for i, idx := range indices {
+		if i == cursor {
+			b.WriteString(selectedStyle.Render("> " + items[idx]))
+		} else {
+			b.WriteString("  " + items[idx])
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: Navigate  Enter: Select  Esc: Cancel"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderHistoryPicker renders the Ctrl+R recent-search picker.
+func (m Model) renderHistoryPicker() string {
+	indices := filterPickerItems(m.matcher, m.pickerQuery, m.history)
+	return renderPickerList("Search History", m.pickerQuery, m.history, indices, m.pickerCursor)
+}
+
+// renderSavedQueryPicker renders the Ctrl+O saved-query picker.
+func (m Model) renderSavedQueryPicker() string {
+	names := savedQueryNames(m.savedQueries)
+	indices := filterPickerItems(m.matcher, m.pickerQuery, names)
+	return renderPickerList("Saved Queries", m.pickerQuery, names, indices, m.pickerCursor)
+}
+
+// renderSaveQueryPrompt renders the Ctrl+S save-query name prompt.
+func (m Model) renderSaveQueryPrompt() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Save Query"))
+	b.WriteString("\n\n")
+
+	b.WriteString(headerStyle.Render("Name: "))
+	b.WriteString(formActiveStyle.Render(m.saveQueryName + "_"))
+	b.WriteString("\n\n")
+
+	b.WriteString(helpStyle.Render("Enter: Save  Esc: Cancel"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderDiskBrowser renders the disk contents modal opened with space
+// (see openDiskBrowser): every file on the browsed disk image, filetype
+// and block count, with Enter mounting the disk and running the
+// highlighted PRG.
+func (m Model) renderDiskBrowser() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Disk Contents - %s", m.diskBrowserEntry.Name)))
+	b.WriteString("\n\n")
+
+	if len(m.diskBrowserDirEntries) == 0 {
+		b.WriteString(dimStyle.Render("No files found"))
+		b.WriteString("\n")
+	}
+	for i, e := range m.diskBrowserDirEntries {
+		line := fmt.Sprintf("%-16s %-3s %3d blk", e.filename, d64FileTypeName(e.fileType), e.blockCount)
+		if i == m.diskBrowserCursor {
+			b.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: Navigate  Enter: Mount & Run PRG  Esc/Space: Close"))
 	b.WriteString("\n")
 
 	return b.String()
@@ -690,31 +1660,39 @@ func boolToToggle(v bool) string {
 	return "[ ]"
 }
 
-// formatEntry formats a single entry for display.
-func (m Model) formatEntry(entry ReleaseEntry, selected bool) string {
-	// Format: "> Name                    (Group, Year)         .ext".
+// formatEntry formats a single entry for display, column-aligned to
+// match the columns sortField can order by. positions, when non-nil,
+// are rune indices into entry.Name that matched the current fuzzy query
+// and are highlighted via matchStyle.
+func (m Model) formatEntry(entry ReleaseEntry, selected bool, positions []int) string {
+	// Format: "> Name                    Group          Year  .ext".
 	cursor := "  "
 	if selected {
 		cursor = "> "
 	}
 
-	// Truncate name if too long.
+	// Truncate name if too long. Truncation invalidates match positions
+	// (they index into the untruncated name), so drop highlighting rather
+	// than risk marking the wrong runes.
 	name := entry.Name
 	maxNameLen := 30
 	if len(name) > maxNameLen {
 		name = name[:maxNameLen-3] + "..."
+		positions = nil
 	}
 
-	// Format group/year.
-	meta := ""
-	if entry.Group != "" || entry.Year != "" {
-		meta = fmt.Sprintf("(%s, %s)", entry.Group, entry.Year)
+	// Pad to column width before highlighting: highlightRunes only wraps
+	// individual runes in place, so the padded width is unaffected.
+	paddedName := fmt.Sprintf("%-32s", name)
+	if len(positions) > 0 {
+		paddedName = highlightRunes(paddedName, positions)
 	}
 
-	// Format extension.
+	group := fmt.Sprintf("%-15s", truncateColumn(entry.Group, 15))
+	year := fmt.Sprintf("%-6s", entry.Year)
 	ext := "." + entry.FileType
 
-	line := fmt.Sprintf("%s%-32s  %-25s  %s", cursor, name, meta, ext)
+	line := fmt.Sprintf("%s%s  %s %s  %s", cursor, paddedName, group, year, ext)
 
 	if selected {
 		return selectedStyle.Render(line)
@@ -722,10 +1700,180 @@ func (m Model) formatEntry(entry ReleaseEntry, selected bool) string {
 	return line
 }
 
-// applyFilters filters entries based on category and search query.
+// truncateColumn shortens s to at most width characters, appending "..."
+// when truncated, for column-aligned display in formatEntry.
+func truncateColumn(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return s[:width]
+	}
+	return s[:width-3] + "..."
+}
+
+// highlightRunes wraps the runes of s at the given indices in matchStyle,
+// used to show which characters satisfied a fuzzy query.
+func highlightRunes(s string, positions []int) string {
+	if len(positions) == 0 {
+		return s
+	}
+
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(s) {
+		if marked[i] {
+			b.WriteString(matchStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// filterCandidate holds one surviving entry pending sort and assignment
+// into the Model's parallel filtered* slices.
+type filterCandidate struct {
+	idx       int
+	score     int
+	positions []int // Matched rune indices into the entry's Name, for highlighting.
+	name      string
+}
+
+// sortCandidates orders candidates by descending score with a stable
+// secondary sort on name, the rank used in fuzzy mode. Substring mode
+// orders by sortCandidatesByField instead.
+func sortCandidates(candidates []filterCandidate) {
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].score != candidates[b].score {
+			return candidates[a].score > candidates[b].score
+		}
+		return candidates[a].name < candidates[b].name
+	})
+}
+
+// filterPickerItems fuzzy-matches query against items using matcher,
+// returning the indices of matching items sorted by descending score -
+// the ranking used by the history and saved-query pickers. An empty
+// query matches every item and preserves its original order.
+func filterPickerItems(matcher Matcher, query string, items []string) []int {
+	if query == "" {
+		indices := make([]int, len(items))
+		for i := range items {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	type pickerCandidate struct {
+		idx   int
+		score int
+	}
+	var candidates []pickerCandidate
+	for i, item := range items {
+		if score, _, ok := matcher.Match(query, item); ok {
+			candidates = append(candidates, pickerCandidate{idx: i, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].score > candidates[b].score
+	})
+
+	indices := make([]int, len(candidates))
+	for i, c := range candidates {
+		indices[i] = c.idx
+	}
+	return indices
+}
+
+// assignFiltered writes sorted candidates into the Model's parallel
+// filtered* slices and clamps the cursor if it fell out of bounds.
+func (m *Model) assignFiltered(candidates []filterCandidate) {
+	m.filteredResults = make([]int, len(candidates))
+	m.filteredScores = make([]int, len(candidates))
+	m.filteredPositions = make([][]int, len(candidates))
+	for i, c := range candidates {
+		m.filteredResults[i] = c.idx
+		m.filteredScores[i] = c.score
+		m.filteredPositions[i] = c.positions
+	}
+
+	if m.cursor >= len(m.filteredResults) {
+		m.cursor = 0
+		m.scrollOffset = 0
+	}
+}
+
+// queryMatch matches target against query using the model's current
+// search mode: plain substring containment by default, or m.matcher's
+// scored fuzzy matching when fuzzyMode is on. An empty query always
+// matches with a zero score.
+func (m *Model) queryMatch(query, target string) (score int, positions []int, ok bool) {
+	if query == "" {
+		return 0, nil, true
+	}
+	if !m.fuzzyMode {
+		if strings.Contains(strings.ToLower(target), strings.ToLower(query)) {
+			return 0, nil, true
+		}
+		return 0, nil, false
+	}
+	return m.matcher.Match(query, target)
+}
+
+// applyFilters filters entries based on the normal search bar. The bar
+// doubles as the compact query DSL (query.go): once searchQuery contains
+// a structured field:value predicate, filtering is handed off to
+// applyAdvancedFilters so a single input box drives both plain
+// substring/fuzzy search and the advanced criteria. Parse errors surface
+// in statusMessage (rendered red) without touching the previous results
+// or clearing the query.
 func (m *Model) applyFilters() {
-	m.filteredResults = make([]int, 0)
-	query := strings.ToLower(m.searchQuery)
+	pq, err := parseQuery(m.searchQuery)
+	if err != nil {
+		m.statusMessage = err.Error()
+		m.statusIsError = true
+		return
+	}
+
+	if !pq.Structured {
+		m.parsedQuery = nil
+		m.applySimpleFilters()
+		return
+	}
+
+	if pq.Category != "" {
+		if cat := m.resolveCategory(pq.Category); cat != "" {
+			m.selectedCategory = cat
+		}
+	}
+	m.parsedQuery = &pq
+	m.applyAdvancedFilters()
+}
+
+// resolveCategory matches name case-insensitively against
+// m.index.CategoryOrder, returning the canonical category name or "" if
+// there is no match.
+func (m *Model) resolveCategory(name string) string {
+	for _, cat := range m.index.CategoryOrder {
+		if strings.EqualFold(cat, name) {
+			return cat
+		}
+	}
+	return ""
+}
+
+// applySimpleFilters filters entries based on category and search query,
+// matching against the Name and Group fields and keeping the
+// higher-scoring field's match positions for highlighting. This is the
+// plain substring/fuzzy mode used when the search bar contains no
+// structured query DSL predicate.
+func (m *Model) applySimpleFilters() {
+	var candidates []filterCandidate
 
 	for i, entry := range m.index.Entries {
 		// Category filter.
@@ -733,29 +1881,37 @@ func (m *Model) applyFilters() {
 			continue
 		}
 
-		// Search filter.
-		if query != "" {
-			nameMatch := strings.Contains(strings.ToLower(entry.Name), query)
-			groupMatch := strings.Contains(strings.ToLower(entry.Group), query)
-			if !nameMatch && !groupMatch {
-				continue
-			}
+		nameScore, namePositions, nameOK := m.queryMatch(m.searchQuery, entry.Name)
+		groupScore, _, groupOK := m.queryMatch(m.searchQuery, entry.Group)
+		if m.searchQuery != "" && !nameOK && !groupOK {
+			continue
+		}
+
+		score, positions := nameScore, namePositions
+		if groupScore > score {
+			score, positions = groupScore, nil // Highlighting only covers the Name field.
 		}
 
-		m.filteredResults = append(m.filteredResults, i)
+		candidates = append(candidates, filterCandidate{idx: i, score: score, positions: positions, name: entry.Name})
 	}
 
-	// Reset cursor if out of bounds.
-	if m.cursor >= len(m.filteredResults) {
-		m.cursor = 0
-		m.scrollOffset = 0
+	if m.fuzzyMode {
+		sortCandidates(candidates)
+	} else {
+		sortCandidatesByField(candidates, m.index.Entries, m.sortField, m.sortDescending)
 	}
+	m.assignFiltered(candidates)
 }
 
-// applyAdvancedFilters filters entries based on AdvancedSearch criteria.
+// applyAdvancedFilters filters entries based on AdvancedSearch criteria,
+// sourced from the advanced search form (m.advSearch) or, when the
+// normal search bar's query DSL produced one, m.parsedQuery.
 func (m *Model) applyAdvancedFilters() {
-	m.filteredResults = make([]int, 0)
+	var candidates []filterCandidate
 	as := m.advSearch
+	if m.parsedQuery != nil {
+		as = m.parsedQuery.Adv
+	}
 
 	for i, entry := range m.index.Entries {
 		// Category filter (still applies in advanced mode).
@@ -763,8 +1919,19 @@ func (m *Model) applyAdvancedFilters() {
 			continue
 		}
 
+		// Query DSL negated terms and free-text fallback tokens.
+		if m.parsedQuery != nil {
+			if !matchesExcludes(entry, m.parsedQuery.Excludes) {
+				continue
+			}
+			if !matchesFreeTerms(entry, m.parsedQuery.FreeTerms) {
+				continue
+			}
+		}
+
 		// Title filter.
-		if as.Title != "" && !strings.Contains(strings.ToLower(entry.Name), strings.ToLower(as.Title)) {
+		titleScore, titlePositions, titleOK := m.queryMatch(as.Title, entry.Name)
+		if as.Title != "" && !titleOK {
 			continue
 		}
 
@@ -855,14 +2022,15 @@ func (m *Model) applyAdvancedFilters() {
 			}
 		}
 
-		m.filteredResults = append(m.filteredResults, i)
+		candidates = append(candidates, filterCandidate{idx: i, score: titleScore, positions: titlePositions, name: entry.Name})
 	}
 
-	// Reset cursor if out of bounds.
-	if m.cursor >= len(m.filteredResults) {
-		m.cursor = 0
-		m.scrollOffset = 0
+	if m.fuzzyMode {
+		sortCandidates(candidates)
+	} else {
+		sortCandidatesByField(candidates, m.index.Entries, m.sortField, m.sortDescending)
 	}
+	m.assignFiltered(candidates)
 }
 
 // adjustScroll adjusts scroll offset to keep cursor visible.
@@ -879,10 +2047,13 @@ func (m *Model) adjustScroll() {
 	}
 }
 
-// refreshIndex reloads the Assembly64 index from disk.
+// refreshIndex reloads the Assembly64 index from disk, along with any
+// extra providers configured in providers.yaml. Only reachable in
+// legacy mode (see the Ctrl+L key handling above), so the Assembly64
+// provider is forced to rescan rather than trying a JSON database.
 func (m *Model) refreshIndex() tea.Cmd {
 	return func() tea.Msg {
-		index, err := loadAssembly64Index(m.assembly64Path)
+		index, err := loadMergedIndex(m.assembly64Path, m.dbPath, true)
 		if err != nil {
 			return refreshMsg{err: fmt.Errorf("failed to refresh index: %w", err)}
 		}
@@ -890,41 +2061,193 @@ func (m *Model) refreshIndex() tea.Cmd {
 	}
 }
 
-// loadSelectedEntry loads the selected entry to C64 Ultimate.
-func (m *Model) loadSelectedEntry() tea.Cmd {
+// loadJob tracks one background load queued with Enter (runAfter=true)
+// or m (runAfter=false, mount-only); see enqueueSelectedEntry. It's
+// rendered as a line in renderFooter via renderJobs, and stays listed
+// once finished (done/failed/canceled) until cleared with D so a
+// failure doesn't silently disappear.
+type loadJob struct {
+	id       int
+	entry    ReleaseEntry
+	runAfter bool
+	state    string // queued, downloading, reading, uploading, mounting, running, done, failed, canceled
+	progress int    // 0-100, meaningful while state is "downloading" or "uploading".
+	err      error
+	cancel   context.CancelFunc
+}
+
+// jobUpdateMsg reports a loadJob's progress, or - when done is true -
+// its final outcome (err nil on success).
+type jobUpdateMsg struct {
+	jobID    int
+	state    string
+	progress int
+	done     bool
+	err      error
+}
+
+// waitForJobUpdate blocks on m.jobUpdates and returns the next update as
+// a tea.Msg. Init starts this running, and Update's jobUpdateMsg case
+// re-issues it after every message so the listener never stops.
+func (m Model) waitForJobUpdate() tea.Cmd {
+	updates := m.jobUpdates
 	return func() tea.Msg {
-		if len(m.filteredResults) == 0 {
-			return statusMsg{err: fmt.Errorf("no entry selected")}
+		return <-updates
+	}
+}
+
+// applyJobUpdate folds a jobUpdateMsg into the matching loadJob.
+func (m *Model) applyJobUpdate(msg jobUpdateMsg) {
+	for _, j := range m.jobs {
+		if j.id != msg.jobID {
+			continue
+		}
+		j.state = msg.state
+		j.progress = msg.progress
+		if msg.done {
+			j.err = msg.err
+			if msg.err != nil {
+				j.state = "failed"
+			} else if j.state != "canceled" {
+				j.state = "done"
+			}
 		}
+		return
+	}
+}
 
-		entry := m.index.Entries[m.filteredResults[m.cursor]]
+// cancelLastPendingJob cancels and marks canceled the most recently
+// queued job that hasn't finished yet - the d key's target, since the
+// job list has no selection cursor of its own.
+func (m *Model) cancelLastPendingJob() {
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		j := m.jobs[i]
+		if j.state == "done" || j.state == "failed" || j.state == "canceled" {
+			continue
+		}
+		j.cancel()
+		j.state = "canceled"
+		m.statusIsError = false
+		m.statusMessage = fmt.Sprintf("Canceled: %s", j.entry.Name)
+		return
+	}
+}
 
-		// Read file.
-		data, err := os.ReadFile(entry.FullPath)
-		if err != nil {
-			return statusMsg{err: fmt.Errorf("failed to read file: %w", err)}
-		}
-
-		// Call appropriate API based on file type.
-		var loadErr error
-		switch entry.FileType {
-		case "d64", "d71", "d81", "g64", "g71":
-			// Use existing runDiskImage (auto-runs first PRG).
-			loadErr = m.apiClient.runDiskImage(data, entry.FileType, filepath.Base(entry.FullPath))
-		case "prg":
-			loadErr = m.apiClient.runPRG(data)
-		case "crt":
-			loadErr = m.apiClient.runCRT(data)
-		default:
-			return statusMsg{err: fmt.Errorf("unsupported file type: %s", entry.FileType)}
+// clearFinishedJobs drops done/failed/canceled jobs from the list (D),
+// keeping only what's still queued or in flight.
+func (m *Model) clearFinishedJobs() {
+	active := m.jobs[:0]
+	for _, j := range m.jobs {
+		if j.state != "done" && j.state != "failed" && j.state != "canceled" {
+			active = append(active, j)
 		}
+	}
+	m.jobs = active
+}
+
+// enqueueSelectedEntry queues the cursor's entry as a background load
+// job - runAfter false is the "load without run" mode (mount the disk
+// image and leave it, don't call runPRG) so several disks can be staged
+// in /Temp and swapped between without waiting on each other.
+func (m *Model) enqueueSelectedEntry(runAfter bool) tea.Cmd {
+	if len(m.filteredResults) == 0 {
+		return func() tea.Msg { return statusMsg{err: fmt.Errorf("no entry selected")} }
+	}
+
+	entry := m.index.Entries[m.filteredResults[m.cursor]]
 
-		if loadErr != nil {
-			return statusMsg{err: fmt.Errorf("failed to load: %w", loadErr)}
+	m.nextJobID++
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &loadJob{id: m.nextJobID, entry: entry, runAfter: runAfter, state: "queued", cancel: cancel}
+	m.jobs = append(m.jobs, job)
+
+	apiClient := m.apiClient
+	updates := m.jobUpdates
+	jobID := job.id
+	cache := m.downloadCache
+
+	return func() tea.Msg {
+		go runLoadJob(ctx, apiClient, entry, runAfter, jobID, updates, cache)
+		return nil
+	}
+}
+
+// runLoadJob performs one queued load in the background, publishing
+// state/progress transitions (queued -> downloading (n%, URL entries
+// with a cache) or reading -> uploading (n%) -> running, or mounting for
+// a load without run -> done) on updates as it goes. It runs in its own goroutine, independent of the
+// tea.Cmd that started it, so multiple jobs can be in flight at once.
+// cache may be nil, in which case a URL entry is fetched straight into
+// memory instead of through the on-disk cache.
+func runLoadJob(ctx context.Context, apiClient *APIClient, entry ReleaseEntry, runAfter bool, jobID int, updates chan<- jobUpdateMsg, cache *downloadCache) {
+	send := func(state string, progress int) {
+		select {
+		case updates <- jobUpdateMsg{jobID: jobID, state: state, progress: progress}:
+		case <-ctx.Done():
+		}
+	}
+	finish := func(err error) {
+		select {
+		case updates <- jobUpdateMsg{jobID: jobID, done: true, err: err}:
+		case <-ctx.Done():
 		}
+	}
+
+	var data []byte
+	var err error
+	if cache != nil && isURL(entry.FullPath) {
+		send("downloading", 0)
+		data, err = cache.Get(ctx, entry.FullPath, func(sent, total int) {
+			pct := 0
+			if total > 0 {
+				pct = sent * 100 / total
+			}
+			send("downloading", pct)
+		})
+	} else {
+		send("reading", 0)
+		data, err = readFile(entry.FullPath)
+	}
+	if err != nil {
+		finish(fmt.Errorf("failed to read file: %w", err))
+		return
+	}
 
-		return statusMsg{message: fmt.Sprintf("✓ Loaded: %s", entry.Name)}
+	progress := func(sent, total int) {
+		pct := 0
+		if total > 0 {
+			pct = sent * 100 / total
+		}
+		send("uploading", pct)
+	}
+
+	var loadErr error
+	switch entry.FileType {
+	case "d64", "d71", "d81", "g64", "g71":
+		send("uploading", 0)
+		loadErr = apiClient.runDiskImage(ctx, data, entry.FileType, filepath.Base(entry.FullPath), runAfter, progress)
+	case "prg":
+		send("uploading", 0)
+		loadErr = apiClient.runPRG(ctx, data, progress)
+	case "crt":
+		send("uploading", 0)
+		loadErr = apiClient.runCRT(ctx, data, progress)
+	default:
+		finish(fmt.Errorf("unsupported file type: %s", entry.FileType))
+		return
+	}
+
+	if loadErr != nil {
+		finish(fmt.Errorf("failed to load: %w", loadErr))
+		return
+	}
+
+	if runAfter {
+		send("running", 100)
+	} else {
+		send("mounting", 100)
 	}
+	finish(nil)
 }
 
 // statusMsg is a message for status updates.
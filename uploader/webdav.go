@@ -0,0 +1,293 @@
+// Read-only WebDAV mount of the Assembly64 catalog, for clients (Finder,
+// Explorer, rclone) that can't speak our 9P tree (see ninep.go) but do
+// speak WebDAV out of the box. Exposes the same category/entry/info.txt
+// layout.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// StartWebDAVServer starts the WebDAV server.
+func StartWebDAVServer(port int, index *SearchIndex, assembly64Path string) error {
+	handler := &webdav.Handler{
+		FileSystem: &catalogFS{index: index},
+		LockSystem: webdav.NewMemLS(),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				slog.Error("WebDAV request error", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+		},
+	}
+
+	addr := fmt.Sprintf(":%d", port)
+	slog.Info("WebDAV server listening", "port", port)
+	fmt.Printf("WebDAV server listening on :%d\n", port)
+
+	go func() {
+		if err := http.ListenAndServe(addr, handler); err != nil {
+			slog.Error("WebDAV server error", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// catalogFS implements webdav.FileSystem as a read-only view of the
+// catalog; every mutating call returns os.ErrPermission.
+type catalogFS struct {
+	index *SearchIndex
+}
+
+func (fs *catalogFS) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return os.ErrPermission
+}
+
+func (fs *catalogFS) RemoveAll(ctx context.Context, name string) error {
+	return os.ErrPermission
+}
+
+func (fs *catalogFS) Rename(ctx context.Context, oldName, newName string) error {
+	return os.ErrPermission
+}
+
+func (fs *catalogFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE) != 0 {
+		return nil, os.ErrPermission
+	}
+
+	node, err := resolveCatalogPath(fs.index, name)
+	if err != nil {
+		return nil, os.ErrNotExist
+	}
+	return &catalogFile{node: node}, nil
+}
+
+func (fs *catalogFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	node, err := resolveCatalogPath(fs.index, name)
+	if err != nil {
+		return nil, os.ErrNotExist
+	}
+	return node.stat(), nil
+}
+
+// catalogNode is a resolved path within the catalog tree: the root,
+// a category, an entry directory, or a file inside one (the release
+// file itself or a synthesized info.txt).
+type catalogNode struct {
+	name     string
+	index    *SearchIndex
+	category string
+	entry    *ReleaseEntry
+	isInfo   bool
+	isFile   bool // true for the release file itself, as opposed to its containing entry directory
+}
+
+// resolveCatalogPath walks a WebDAV path against the catalog tree, the
+// same shape ninep.go's Walk resolves over 9P.
+func resolveCatalogPath(index *SearchIndex, name string) (*catalogNode, error) {
+	parts := strings.Split(strings.Trim(name, "/"), "/")
+	if len(parts) == 1 && parts[0] == "" {
+		return &catalogNode{name: "/", index: index}, nil
+	}
+
+	// Category.
+	var category string
+	for _, cat := range index.CategoryOrder {
+		if cat != "All" && strings.EqualFold(cat, parts[0]) {
+			category = cat
+			break
+		}
+	}
+	if category == "" {
+		return nil, fmt.Errorf("no such category: %s", parts[0])
+	}
+	if len(parts) == 1 {
+		return &catalogNode{name: category, index: index, category: category}, nil
+	}
+
+	// Entry.
+	var entry *ReleaseEntry
+	for _, idx := range index.ByCategory[category] {
+		if index.Entries[idx].Name == parts[1] {
+			entry = &index.Entries[idx]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("no such entry: %s", parts[1])
+	}
+	if len(parts) == 2 {
+		return &catalogNode{name: entry.Name, index: index, category: category, entry: entry}, nil
+	}
+
+	// File inside an entry directory: the release file or info.txt.
+	if len(parts) == 3 && parts[2] == "info.txt" {
+		return &catalogNode{name: parts[2], index: index, category: category, entry: entry, isInfo: true}, nil
+	}
+	if len(parts) == 3 && parts[2] == entry.Name {
+		return &catalogNode{name: parts[2], index: index, category: category, entry: entry, isFile: true}, nil
+	}
+	return nil, fmt.Errorf("no such path: %s", name)
+}
+
+func (n *catalogNode) stat() os.FileInfo {
+	if n.entry != nil && n.isInfo {
+		return catalogFileInfo{name: "info.txt", size: int64(len(n.infoText()))}
+	}
+	if n.entry != nil && n.isFile {
+		return catalogFileInfo{name: n.entry.Name}
+	}
+	if n.entry != nil {
+		return catalogFileInfo{name: n.entry.Name, isDir: true}
+	}
+	return catalogFileInfo{name: n.name, isDir: true}
+}
+
+// infoText synthesizes the same fields as ninep.go's info.txt: handleInfo's
+// NAME/GROUP/YEAR/CAT/TYPE, plus TRAINER when crack info is known, with no
+// PATH line and no TRAINER|unknown line.
+func (n *catalogNode) infoText() []byte {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("NAME|%s\n", n.entry.Name))
+	b.WriteString(fmt.Sprintf("GROUP|%s\n", n.entry.Group))
+	b.WriteString(fmt.Sprintf("YEAR|%s\n", n.entry.Year))
+	b.WriteString(fmt.Sprintf("CAT|%s\n", n.entry.CategoryName))
+	b.WriteString(fmt.Sprintf("TYPE|%s\n", n.entry.FileType))
+	if n.entry.Crack != nil {
+		b.WriteString(fmt.Sprintf("TRAINER|%d\n", n.entry.Crack.Trainers))
+	}
+	return []byte(b.String())
+}
+
+// children lists the names directly under this node.
+func (n *catalogNode) children() []string {
+	switch {
+	case n.entry != nil:
+		return []string{"info.txt", n.entry.Name}
+	case n.category != "":
+		var names []string
+		for _, idx := range n.index.ByCategory[n.category] {
+			names = append(names, n.index.Entries[idx].Name)
+		}
+		return names
+	default:
+		var names []string
+		for _, cat := range n.index.CategoryOrder {
+			if cat != "All" {
+				names = append(names, cat)
+			}
+		}
+		return names
+	}
+}
+
+// catalogFile implements webdav.File over a resolved catalogNode. Data for
+// the release file itself goes through the shared block cache via readFile.
+type catalogFile struct {
+	node *catalogNode
+	pos  int64
+	data []byte // lazily populated for actual file reads.
+}
+
+func (f *catalogFile) Close() error { return nil }
+
+func (f *catalogFile) Write(p []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *catalogFile) Read(p []byte) (int, error) {
+	if err := f.ensureData(); err != nil {
+		return 0, err
+	}
+	if f.pos >= int64(len(f.data)) {
+		return 0, nil
+	}
+	n := copy(p, f.data[f.pos:])
+	f.pos += int64(n)
+	return n, nil
+}
+
+func (f *catalogFile) Seek(offset int64, whence int) (int64, error) {
+	if err := f.ensureData(); err != nil {
+		return 0, err
+	}
+	switch whence {
+	case io.SeekStart:
+		f.pos = offset
+	case io.SeekCurrent:
+		f.pos += offset
+	case io.SeekEnd:
+		f.pos = int64(len(f.data)) + offset
+	}
+	return f.pos, nil
+}
+
+func (f *catalogFile) ensureData() error {
+	if f.data != nil {
+		return nil
+	}
+	if f.node.isInfo {
+		f.data = f.node.infoText()
+		return nil
+	}
+	if f.node.entry == nil {
+		return fmt.Errorf("not a file")
+	}
+	data, err := readFile(f.node.entry.FullPath)
+	if err != nil {
+		return fmt.Errorf("reading entry file: %w", err)
+	}
+	f.data = data
+	return nil
+}
+
+func (f *catalogFile) Readdir(count int) ([]os.FileInfo, error) {
+	if f.node.isInfo || f.node.isFile {
+		return nil, fmt.Errorf("not a directory: %s", f.node.name)
+	}
+
+	var infos []os.FileInfo
+	switch {
+	case f.node.entry != nil:
+		infos = append(infos, catalogFileInfo{name: "info.txt", size: int64(len(f.node.infoText()))})
+		infos = append(infos, catalogFileInfo{name: f.node.entry.Name})
+	default:
+		for _, name := range f.node.children() {
+			infos = append(infos, catalogFileInfo{name: name, isDir: true})
+		}
+	}
+	return infos, nil
+}
+
+func (f *catalogFile) Stat() (os.FileInfo, error) {
+	return f.node.stat(), nil
+}
+
+// catalogFileInfo implements os.FileInfo for catalog tree entries.
+type catalogFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (fi catalogFileInfo) Name() string { return fi.name }
+func (fi catalogFileInfo) Size() int64  { return fi.size }
+func (fi catalogFileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0555
+	}
+	return 0444
+}
+func (fi catalogFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi catalogFileInfo) IsDir() bool        { return fi.isDir }
+func (fi catalogFileInfo) Sys() interface{}   { return nil }